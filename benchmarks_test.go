@@ -25,9 +25,6 @@ import (
 )
 
 func benchmarkFromFile(b *testing.B, filename string) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
 	msg := loadCompressed(b, filename)
 
 	b.Run("copy", func(b *testing.B) {
@@ -96,7 +93,6 @@ func BenchmarkParseTwitterEscaped(b *testing.B) { benchmarkFromFile(b, "twittere
 func BenchmarkParseUpdate_center(b *testing.B)  { benchmarkFromFile(b, "update-center") }
 
 func benchmarkJsoniter(b *testing.B, filename string) {
-
 	msg := loadCompressed(b, filename)
 
 	b.SetBytes(int64(len(msg)))
@@ -113,7 +109,6 @@ func benchmarkJsoniter(b *testing.B, filename string) {
 }
 
 func benchmarkEncodingJson(b *testing.B, filename string) {
-
 	msg := loadCompressed(b, filename)
 
 	b.SetBytes(int64(len(msg)))
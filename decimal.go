@@ -0,0 +1,179 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// parseDecimal returns the valid number text as a decimal, mantissa * 10^exp.
+// All digits are kept, so 1.50 has mantissa 150 and exponent -2.
+// ok is false if the mantissa doesn't fit in an int64 or the exponent in an int32.
+func parseDecimal(text []byte) (mantissa int64, exp int32, ok bool) {
+	i := 0
+	neg := text[0] == '-'
+	if neg {
+		i++
+	}
+	var m uint64
+	var e int64
+	point := false
+	for ; i < len(text); i++ {
+		c := text[i]
+		if c == '.' {
+			point = true
+			continue
+		}
+		if c == 'e' || c == 'E' {
+			v, err := strconv.ParseInt(string(text[i+1:]), 10, 32)
+			if err != nil {
+				return 0, 0, false
+			}
+			e += v
+			break
+		}
+		d := uint64(c - '0')
+		if m > (math.MaxInt64-d)/10 {
+			return 0, 0, false
+		}
+		m = m*10 + d
+		if point {
+			e--
+		}
+	}
+	if e < math.MinInt32 || e > math.MaxInt32 {
+		return 0, 0, false
+	}
+	mantissa = int64(m)
+	if neg {
+		mantissa = -mantissa
+	}
+	return mantissa, int32(e), true
+}
+
+// exactPow10 are the powers of 10 that can be represented exactly by a float64.
+var exactPow10 = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11,
+	1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22}
+
+// decimalFloat returns the float64 closest to mantissa * 10^exp.
+func decimalFloat(mantissa int64, exp int32) float64 {
+	if mantissa > -1<<53 && mantissa < 1<<53 && exp > -int32(len(exactPow10)) && exp < int32(len(exactPow10)) {
+		// Both values are exact, so the result is correctly rounded.
+		if exp < 0 {
+			return float64(mantissa) / exactPow10[-exp]
+		}
+		return float64(mantissa) * exactPow10[exp]
+	}
+	var tmp [32]byte
+	f, _ := strconv.ParseFloat(unsafeBytesToString(appendDecimalExp(tmp[:0], mantissa, exp)), 64)
+	return f
+}
+
+// decimalInt returns mantissa * 10^exp truncated to an integer.
+func decimalInt(mantissa int64, exp int32) (int64, error) {
+	for ; exp < 0 && mantissa != 0; exp++ {
+		mantissa /= 10
+	}
+	for ; exp > 0 && mantissa != 0; exp-- {
+		if mantissa > math.MaxInt64/10 || mantissa < math.MinInt64/10 {
+			return 0, errors.New("decimal value overflows int64")
+		}
+		mantissa *= 10
+	}
+	return mantissa, nil
+}
+
+// decimalUint returns mantissa * 10^exp truncated to an unsigned integer.
+func decimalUint(mantissa int64, exp int32) (uint64, error) {
+	if mantissa < 0 {
+		return 0, errors.New("decimal value is negative. cannot convert to uint")
+	}
+	m := uint64(mantissa)
+	for ; exp < 0 && m != 0; exp++ {
+		m /= 10
+	}
+	for ; exp > 0 && m != 0; exp-- {
+		if m > math.MaxUint64/10 {
+			return 0, errors.New("decimal value overflows uint64")
+		}
+		m *= 10
+	}
+	return m, nil
+}
+
+// decimalBigInt returns mantissa * 10^exp as an integer.
+// An error is returned if the value is not an integer.
+func decimalBigInt(mantissa int64, exp int32) (*big.Int, error) {
+	v := big.NewInt(mantissa)
+	if mantissa == 0 {
+		return v, nil
+	}
+	if exp < 0 {
+		// The mantissa has at most 19 digits.
+		if exp < -19 {
+			return nil, errors.New("decimal value is not an integer")
+		}
+		var rem big.Int
+		v.QuoRem(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil), &rem)
+		if rem.Sign() != 0 {
+			return nil, errors.New("decimal value is not an integer")
+		}
+		return v, nil
+	}
+	if int64(exp) > maxBigIntBits/4 {
+		return nil, errors.New("decimal value is too large")
+	}
+	return v.Mul(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)), nil
+}
+
+// appendDecimal appends mantissa * 10^exp as a JSON number to dst.
+// The number will parse to the same mantissa and exponent.
+func appendDecimal(dst []byte, mantissa int64, exp int32) []byte {
+	m := uint64(mantissa)
+	if mantissa < 0 {
+		m = uint64(-mantissa)
+	}
+	var tmp [20]byte
+	digits := strconv.AppendUint(tmp[:0], m, 10)
+	frac := -int(exp)
+	if exp >= 0 || frac-len(digits) > 6 {
+		return appendDecimalExp(dst, mantissa, exp)
+	}
+	if mantissa < 0 {
+		dst = append(dst, '-')
+	}
+	if frac < len(digits) {
+		dst = append(dst, digits[:len(digits)-frac]...)
+		dst = append(dst, '.')
+		return append(dst, digits[len(digits)-frac:]...)
+	}
+	dst = append(dst, '0', '.')
+	for i := len(digits); i < frac; i++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, digits...)
+}
+
+// appendDecimalExp appends mantissa * 10^exp in exponent notation to dst.
+func appendDecimalExp(dst []byte, mantissa int64, exp int32) []byte {
+	dst = strconv.AppendInt(dst, mantissa, 10)
+	dst = append(dst, 'e')
+	return strconv.AppendInt(dst, int64(exp), 10)
+}
@@ -0,0 +1,107 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		text     string
+		mantissa int64
+		exp      int32
+		ok       bool
+		json     string
+	}{
+		{text: "1.50", mantissa: 150, exp: -2, ok: true, json: "1.50"},
+		{text: "-0.05", mantissa: -5, exp: -2, ok: true, json: "-0.05"},
+		{text: "1e3", mantissa: 1, exp: 3, ok: true, json: "1e3"},
+		{text: "12.5E-3", mantissa: 125, exp: -4, ok: true, json: "0.0125"},
+		{text: "-1e-7", mantissa: -1, exp: -7, ok: true, json: "-0.0000001"},
+		{text: "1e-8", mantissa: 1, exp: -8, ok: true, json: "1e-8"},
+		{text: "0.000", mantissa: 0, exp: -3, ok: true, json: "0.000"},
+		{text: "922337203685477580.7", mantissa: 9223372036854775807, exp: -1, ok: true, json: "922337203685477580.7"},
+		{text: "92233720368547758070.0"},
+		{text: "1e3000000000"},
+		{text: "1e-2147483648", mantissa: 1, exp: -2147483648, ok: true, json: "1e-2147483648"},
+		{text: "0.1e-2147483648"},
+	}
+	for _, tt := range tests {
+		mantissa, exp, ok := parseDecimal([]byte(tt.text))
+		if ok != tt.ok || mantissa != tt.mantissa || exp != tt.exp {
+			t.Errorf("%s: got %d, %d, %v, want %d, %d, %v", tt.text, mantissa, exp, ok, tt.mantissa, tt.exp, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		got := string(appendDecimal(nil, mantissa, exp))
+		if got != tt.json {
+			t.Errorf("%s: got %s, want %s", tt.text, got, tt.json)
+		}
+		if m, e, _ := parseDecimal([]byte(got)); m != mantissa || e != exp {
+			t.Errorf("%s: %s parsed to %d, %d", tt.text, got, m, e)
+		}
+	}
+}
+
+func TestDecimalFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 100000; i++ {
+		mantissa := rng.Int63() >> uint(rng.Intn(63))
+		if rng.Intn(2) == 0 {
+			mantissa = -mantissa
+		}
+		exp := int32(rng.Intn(80) - 40)
+		text := string(appendDecimalExp(nil, mantissa, exp))
+		want, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decimalFloat(mantissa, exp); got != want {
+			t.Fatalf("%s: got %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestDecimalInt(t *testing.T) {
+	tests := []struct {
+		mantissa int64
+		exp      int32
+		want     string
+	}{
+		{mantissa: 1999, exp: -2, want: "19"},
+		{mantissa: -1999, exp: -2, want: "-19"},
+		{mantissa: 5, exp: -30, want: "0"},
+		{mantissa: 15, exp: 3, want: "15000"},
+		{mantissa: 9, exp: 18, want: "9000000000000000000"},
+		{mantissa: 1, exp: 19, want: "error"},
+	}
+	for _, tt := range tests {
+		v, err := decimalInt(tt.mantissa, tt.exp)
+		got := strconv.FormatInt(v, 10)
+		if err != nil {
+			got = "error"
+		}
+		if got != tt.want {
+			t.Errorf("%de%d: got %s, want %s", tt.mantissa, tt.exp, got, tt.want)
+		}
+	}
+}
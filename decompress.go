@@ -0,0 +1,101 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"io"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic bytes of the compressed formats.
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	s2Magic    = []byte{0xff, 0x06, 0x00, 0x00} // stream identifier chunk of s2 and snappy
+	bzip2Magic = []byte("BZh")
+)
+
+// decompressor decompresses a stream,
+// with the format detected from the first bytes when it is first read.
+type decompressor struct {
+	in     *bufio.Reader
+	r      io.Reader // decompressed input
+	zstd   *zstd.Decoder
+	closer io.Closer // closed with the decompressor, may be nil
+}
+
+func newDecompressor(r io.Reader, closer io.Closer) *decompressor {
+	return &decompressor{in: bufio.NewReader(r), closer: closer}
+}
+
+func (d *decompressor) Read(p []byte) (int, error) {
+	if d.r == nil {
+		if err := d.detect(); err != nil {
+			return 0, err
+		}
+	}
+	return d.r.Read(p)
+}
+
+// detect sets the decompressed input from the first bytes of the input.
+func (d *decompressor) detect() error {
+	magic, err := d.in.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(d.in)
+		if err != nil {
+			return err
+		}
+		d.r = zr
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(d.in)
+		if err != nil {
+			return err
+		}
+		d.zstd = zr
+		d.r = zr
+	case bytes.HasPrefix(magic, s2Magic):
+		d.r = s2.NewReader(d.in)
+	case bytes.HasPrefix(magic, bzip2Magic) && len(magic) > len(bzip2Magic) &&
+		magic[len(bzip2Magic)] >= '1' && magic[len(bzip2Magic)] <= '9':
+		// The block size follows the magic.
+		d.r = bzip2.NewReader(d.in)
+	default:
+		d.r = d.in
+	}
+	return nil
+}
+
+// Close releases the decoder and closes the underlying input, if any.
+func (d *decompressor) Close() error {
+	if d.zstd != nil {
+		d.zstd.Close()
+	}
+	if d.closer != nil {
+		return d.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// bzip2Records is "{\"id\":0}\n" to "{\"id\":19}\n" compressed with bzip2.
+const bzip2Records = "425a68393141592653590b62f6db00004b5980001010007ff00420000a200054348347ea806824d543264687a9410f421410bfd20aaaaaaaab7085041132ba4ca162d9d6f9df400001f1772453850900b62f6db0"
+
+func TestStreamDecompression(t *testing.T) {
+	var sb strings.Builder
+	var want []interface{}
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&sb, "{\"id\":%d}\n", i)
+		want = append(want, map[string]interface{}{"id": int64(i)})
+	}
+	input := []byte(sb.String())
+
+	compressed := map[string][]byte{"plain": input}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	gw.Write(input)
+	gw.Close()
+	compressed["gzip"] = append([]byte(nil), buf.Bytes()...)
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed["zstd"] = zw.EncodeAll(input, nil)
+	zw.Close()
+	for name, opts := range map[string][]s2.WriterOption{"s2": nil, "snappy": {s2.WriterSnappyCompat()}} {
+		buf.Reset()
+		sw := s2.NewWriter(&buf, opts...)
+		sw.Write(input)
+		sw.Close()
+		compressed[name] = append([]byte(nil), buf.Bytes()...)
+	}
+	compressed["bzip2"], err = hex.DecodeString(bzip2Records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for name, data := range compressed {
+		got, _, err := streamNDRecords(bytes.NewReader(data), WithStreamDecompression(), WithStreamChunkSize(16))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, _, err = streamValues(func(res chan<- Stream) {
+			ParseNDFile(context.Background(), file, res, nil, WithStreamChunkSize(16))
+		})
+		if err != nil {
+			t.Fatalf("%s file: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s file: got %v, want %v", name, got, want)
+		}
+	}
+
+	// Compressed input is only detected when asked for.
+	if _, _, err := streamNDRecords(bytes.NewReader(compressed["gzip"])); err == nil {
+		t.Error("gzip without decompression: expected error")
+	}
+	// Corrupt input.
+	gz := compressed["gzip"]
+	if _, _, err := streamNDRecords(bytes.NewReader(gz[:len(gz)/2]), WithStreamDecompression()); err == nil {
+		t.Error("truncated gzip: expected error")
+	}
+	_, _, err = streamValues(func(res chan<- Stream) {
+		ParseNDFile(context.Background(), filepath.Join(dir, "missing"), res, nil)
+	})
+	if !os.IsNotExist(err) {
+		t.Errorf("missing file: got error %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+// objectKeys holds the keys of an object while it is parsed.
+type objectKeys struct {
+	keys map[string]uint64 // tape location of the last key with each name
+	dup  bool              // a key was duplicated
+}
+
+// beginObjectKeys starts collecting the keys of a new object.
+func (pj *internalParsedJson) beginObjectKeys() {
+	depth := len(pj.containingScopeOffset)
+	for len(pj.objectKeys) <= depth {
+		pj.objectKeys = append(pj.objectKeys, objectKeys{})
+	}
+	k := &pj.objectKeys[depth]
+	k.dup = false
+	if len(k.keys) > 64 || k.keys == nil {
+		// Clearing a map iterates all buckets, so replace large maps.
+		k.keys = make(map[string]uint64)
+		return
+	}
+	for key := range k.keys {
+		delete(k.keys, key)
+	}
+}
+
+// lastKey returns the key that was last written to the tape.
+func (pj *internalParsedJson) lastKey() []byte {
+	loc := len(pj.Tape) - 2
+	key, _ := pj.stringByteAt(pj.Tape[loc]&JSONVALUEMASK, pj.Tape[loc+1])
+	return key
+}
+
+// addObjectKey adds the key that was last written to the tape to the current object.
+// false is returned if the key is a duplicate that should be rejected.
+func (pj *internalParsedJson) addObjectKey() bool {
+	k := &pj.objectKeys[len(pj.containingScopeOffset)]
+	key := pj.lastKey()
+	if _, ok := k.keys[string(key)]; ok {
+		if pj.duplicateKeys == DuplicateKeysReject {
+			return false
+		}
+		k.dup = true
+	}
+	k.keys[string(key)] = uint64(len(pj.Tape) - 2)
+	return true
+}
+
+// removeDuplicateKeys removes all but the last of each duplicated key
+// from the object that is being closed.
+// The object must be at the end of the tape.
+func (pj *internalParsedJson) removeDuplicateKeys() {
+	k := &pj.objectKeys[len(pj.containingScopeOffset)]
+	if !k.dup {
+		return
+	}
+	start := pj.containingScopeOffset[len(pj.containingScopeOffset)-1] >> retAddressShift
+	tape := pj.Tape
+	dst := start + 1
+	for src := start + 1; src < uint64(len(tape)); {
+		// The key is followed by a single value.
+		end := src + 2 + tapeValueLength(tape, src+2)
+		key, _ := pj.stringByteAt(tape[src]&JSONVALUEMASK, tape[src+1])
+		if k.keys[string(key)] == src {
+			moveTape(tape, dst, src, end)
+			dst += end - src
+		}
+		src = end
+	}
+	pj.Tape = tape[:dst]
+}
+
+// tapeValueLength returns the number of tape entries used by the value at tape[i].
+func tapeValueLength(tape []uint64, i uint64) uint64 {
+	switch Tag(tape[i] >> JSONTAGOFFSET) {
+	case TagString, TagInteger, TagUint, TagFloat, TagNumber, TagDecimal:
+		return 2
+	case TagObjectStart, TagArrayStart:
+		// Containers point past their end.
+		return tape[i]&JSONVALUEMASK - i
+	}
+	return 1
+}
+
+// moveTape moves the entries tape[src:end] to dst, which must be before src,
+// and updates the locations stored in containers.
+func moveTape(tape []uint64, dst, src, end uint64) {
+	delta := src - dst
+	for i := src; i < end; i++ {
+		v := tape[i]
+		switch Tag(v >> JSONTAGOFFSET) {
+		case TagString, TagInteger, TagUint, TagFloat, TagNumber, TagDecimal:
+			tape[i-delta] = v
+			i++
+			v = tape[i]
+		case TagObjectStart, TagObjectEnd, TagArrayStart, TagArrayEnd:
+			v -= delta
+		}
+		tape[i-delta] = v
+	}
+}
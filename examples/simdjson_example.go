@@ -28,9 +28,6 @@ func printKeyHistogram(pj *simdjson.ParsedJson, key string) (err error) {
 }
 
 func main() {
-	if !simdjson.SupportedCPU() {
-		log.Fatal("Unsupported CPU")
-	}
 	msg, err := ioutil.ReadFile("parking-citations.json")
 	if err != nil {
 		log.Fatalf("Failed to load file: %v", err)
@@ -1,4 +1,6 @@
-//+build !noasm !appengine gc
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
+
 // AUTO-GENERATED BY C2GOASM -- DO NOT EDIT
 
 #include "common.h"
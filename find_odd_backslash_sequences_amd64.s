@@ -1,4 +1,6 @@
-//+build !noasm !appengine gc
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
+
 // AUTO-GENERATED BY C2GOASM -- DO NOT EDIT
 
 DATA LCDATA1<>+0x000(SB)/8, $0x5c5c5c5c5c5c5c5c
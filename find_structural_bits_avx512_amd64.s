@@ -1,4 +1,5 @@
-//+build !noasm !appengine gc
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
 
 #include "common.h"
 
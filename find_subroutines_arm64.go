@@ -0,0 +1,89 @@
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
+
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"unsafe"
+)
+
+//go:noescape
+func _classify_block_neon(buf unsafe.Pointer, masks *blockMasks)
+
+// classify_block_neon fills m with the character classes
+// of the first 64 bytes of buf.
+func classify_block_neon(buf []byte, m *blockMasks) {
+	_ = buf[63]
+	_classify_block_neon(unsafe.Pointer(&buf[0]), m)
+}
+
+// classify_block_simd is used by find_structural_bits_in_slice_blocks.
+func classify_block_simd(buf []byte, m *blockMasks) {
+	classify_block_neon(buf, m)
+}
+
+func find_odd_backslash_sequences_neon(buf []byte, prev_iter_ends_odd_backslash *uint64) uint64 {
+	var m blockMasks
+	classify_block_neon(buf, &m)
+	return odd_backslash_sequences(m.backslash, prev_iter_ends_odd_backslash)
+}
+
+func find_quote_mask_and_bits_neon(buf []byte, odd_ends uint64, prev_iter_inside_quote, quote_bits, error_mask *uint64) (quote_mask uint64) {
+	var m blockMasks
+	classify_block_neon(buf, &m)
+	return quote_mask_and_bits(m.quote, m.control, odd_ends, prev_iter_inside_quote, quote_bits, error_mask)
+}
+
+func find_whitespace_and_structurals_neon(buf []byte, whitespace, structurals *uint64) {
+	var m blockMasks
+	classify_block_neon(buf, &m)
+	*whitespace = m.whitespace
+	*structurals = m.structural
+}
+
+func find_newline_delimiters_neon(raw []byte, quoteMask uint64) (mask uint64) {
+	var m blockMasks
+	classify_block_neon(raw, &m)
+	return m.newline &^ quoteMask
+}
+
+func find_structural_bits_neon(buf []byte, prev_iter_ends_odd_backslash *uint64,
+	prev_iter_inside_quote, error_mask *uint64,
+	structurals uint64,
+	prev_iter_ends_pseudo_pred *uint64) uint64 {
+
+	var m blockMasks
+	classify_block_neon(buf, &m)
+
+	quote_bits := uint64(0)
+	odd_ends := odd_backslash_sequences(m.backslash, prev_iter_ends_odd_backslash)
+	quote_mask := quote_mask_and_bits(m.quote, m.control, odd_ends, prev_iter_inside_quote, &quote_bits, error_mask)
+	return finalize_structurals_generic(m.structural, m.whitespace, quote_mask, quote_bits, prev_iter_ends_pseudo_pred)
+}
+
+func find_structural_bits_in_slice_neon(buf []byte, prev_iter_ends_odd_backslash *uint64,
+	prev_iter_inside_quote, error_mask *uint64,
+	prev_iter_ends_pseudo_pred *uint64,
+	indexes *[indexSize]uint32, index *int, carried *uint64, position *uint64,
+	ndjson uint64) (processed uint64) {
+	return find_structural_bits_in_slice_blocks(true, buf, prev_iter_ends_odd_backslash,
+		prev_iter_inside_quote, error_mask,
+		prev_iter_ends_pseudo_pred,
+		indexes, index, carried, position, ndjson)
+}
@@ -0,0 +1,153 @@
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
+
+#include "textflag.h"
+
+// Bit weights used to turn a vector of byte masks into a bit mask.
+DATA bitWeights<>+0x00(SB)/8, $0x8040201008040201
+DATA bitWeights<>+0x08(SB)/8, $0x8040201008040201
+GLOBL bitWeights<>(SB), (RODATA|NOPTR), $16
+
+// MOVEMASK reduces the byte masks in V4-V7 to a bit mask and stores it at off(R1).
+#define MOVEMASK(off) \
+	VAND   V31.B16, V4.B16, V4.B16; \
+	VAND   V31.B16, V5.B16, V5.B16; \
+	VAND   V31.B16, V6.B16, V6.B16; \
+	VAND   V31.B16, V7.B16, V7.B16; \
+	VADDP  V5.B16, V4.B16, V8.B16; \
+	VADDP  V7.B16, V6.B16, V9.B16; \
+	VADDP  V9.B16, V8.B16, V8.B16; \
+	VADDP  V8.B16, V8.B16, V8.B16; \
+	VMOV   V8.D[0], R4; \
+	MOVD   R4, off(R1)
+
+// func _classify_block_neon(buf unsafe.Pointer, masks *blockMasks)
+TEXT ·_classify_block_neon(SB), NOSPLIT, $0-16
+	MOVD buf+0(FP), R0
+	MOVD masks+8(FP), R1
+
+	VLD1 (R0), [V0.B16, V1.B16, V2.B16, V3.B16]
+	MOVD $bitWeights<>(SB), R2
+	VLD1 (R2), [V31.B16]
+
+	MOVD $0x5c, R3
+	VDUP R3, V16.B16 // '\\'
+	MOVD $0x22, R3
+	VDUP R3, V17.B16 // '"'
+	MOVD $0x20, R3
+	VDUP R3, V18.B16 // ' '
+	MOVD $0x09, R3
+	VDUP R3, V19.B16 // '\t'
+	MOVD $0x0a, R3
+	VDUP R3, V20.B16 // '\n'
+	MOVD $0x0d, R3
+	VDUP R3, V21.B16 // '\r'
+	MOVD $0x7b, R3
+	VDUP R3, V22.B16 // '{'
+	MOVD $0x7d, R3
+	VDUP R3, V23.B16 // '}'
+	MOVD $0x3a, R3
+	VDUP R3, V24.B16 // ':'
+	MOVD $0x2c, R3
+	VDUP R3, V25.B16 // ','
+	VEOR V30.B16, V30.B16, V30.B16
+
+	// backslash
+	VCMEQ V16.B16, V0.B16, V4.B16
+	VCMEQ V16.B16, V1.B16, V5.B16
+	VCMEQ V16.B16, V2.B16, V6.B16
+	VCMEQ V16.B16, V3.B16, V7.B16
+	MOVEMASK(0)
+
+	// quote
+	VCMEQ V17.B16, V0.B16, V4.B16
+	VCMEQ V17.B16, V1.B16, V5.B16
+	VCMEQ V17.B16, V2.B16, V6.B16
+	VCMEQ V17.B16, V3.B16, V7.B16
+	MOVEMASK(8)
+
+	// control characters (< 0x20)
+	VUSHR $5, V0.B16, V4.B16
+	VCMEQ V30.B16, V4.B16, V4.B16
+	VUSHR $5, V1.B16, V5.B16
+	VCMEQ V30.B16, V5.B16, V5.B16
+	VUSHR $5, V2.B16, V6.B16
+	VCMEQ V30.B16, V6.B16, V6.B16
+	VUSHR $5, V3.B16, V7.B16
+	VCMEQ V30.B16, V7.B16, V7.B16
+	MOVEMASK(16)
+
+	// whitespace
+	VCMEQ V18.B16, V0.B16, V4.B16
+	VCMEQ V19.B16, V0.B16, V10.B16
+	VORR  V10.B16, V4.B16, V4.B16
+	VCMEQ V20.B16, V0.B16, V10.B16
+	VORR  V10.B16, V4.B16, V4.B16
+	VCMEQ V21.B16, V0.B16, V10.B16
+	VORR  V10.B16, V4.B16, V4.B16
+	VCMEQ V18.B16, V1.B16, V5.B16
+	VCMEQ V19.B16, V1.B16, V10.B16
+	VORR  V10.B16, V5.B16, V5.B16
+	VCMEQ V20.B16, V1.B16, V10.B16
+	VORR  V10.B16, V5.B16, V5.B16
+	VCMEQ V21.B16, V1.B16, V10.B16
+	VORR  V10.B16, V5.B16, V5.B16
+	VCMEQ V18.B16, V2.B16, V6.B16
+	VCMEQ V19.B16, V2.B16, V10.B16
+	VORR  V10.B16, V6.B16, V6.B16
+	VCMEQ V20.B16, V2.B16, V10.B16
+	VORR  V10.B16, V6.B16, V6.B16
+	VCMEQ V21.B16, V2.B16, V10.B16
+	VORR  V10.B16, V6.B16, V6.B16
+	VCMEQ V18.B16, V3.B16, V7.B16
+	VCMEQ V19.B16, V3.B16, V10.B16
+	VORR  V10.B16, V7.B16, V7.B16
+	VCMEQ V20.B16, V3.B16, V10.B16
+	VORR  V10.B16, V7.B16, V7.B16
+	VCMEQ V21.B16, V3.B16, V10.B16
+	VORR  V10.B16, V7.B16, V7.B16
+	MOVEMASK(24)
+
+	// structurals, '[' and ']' are matched by setting 0x20
+	VORR  V18.B16, V0.B16, V11.B16
+	VCMEQ V22.B16, V11.B16, V4.B16
+	VCMEQ V23.B16, V11.B16, V10.B16
+	VORR  V10.B16, V4.B16, V4.B16
+	VCMEQ V24.B16, V0.B16, V10.B16
+	VORR  V10.B16, V4.B16, V4.B16
+	VCMEQ V25.B16, V0.B16, V10.B16
+	VORR  V10.B16, V4.B16, V4.B16
+	VORR  V18.B16, V1.B16, V11.B16
+	VCMEQ V22.B16, V11.B16, V5.B16
+	VCMEQ V23.B16, V11.B16, V10.B16
+	VORR  V10.B16, V5.B16, V5.B16
+	VCMEQ V24.B16, V1.B16, V10.B16
+	VORR  V10.B16, V5.B16, V5.B16
+	VCMEQ V25.B16, V1.B16, V10.B16
+	VORR  V10.B16, V5.B16, V5.B16
+	VORR  V18.B16, V2.B16, V11.B16
+	VCMEQ V22.B16, V11.B16, V6.B16
+	VCMEQ V23.B16, V11.B16, V10.B16
+	VORR  V10.B16, V6.B16, V6.B16
+	VCMEQ V24.B16, V2.B16, V10.B16
+	VORR  V10.B16, V6.B16, V6.B16
+	VCMEQ V25.B16, V2.B16, V10.B16
+	VORR  V10.B16, V6.B16, V6.B16
+	VORR  V18.B16, V3.B16, V11.B16
+	VCMEQ V22.B16, V11.B16, V7.B16
+	VCMEQ V23.B16, V11.B16, V10.B16
+	VORR  V10.B16, V7.B16, V7.B16
+	VCMEQ V24.B16, V3.B16, V10.B16
+	VORR  V10.B16, V7.B16, V7.B16
+	VCMEQ V25.B16, V3.B16, V10.B16
+	VORR  V10.B16, V7.B16, V7.B16
+	MOVEMASK(32)
+
+	// newline
+	VCMEQ V20.B16, V0.B16, V4.B16
+	VCMEQ V20.B16, V1.B16, V5.B16
+	VCMEQ V20.B16, V2.B16, V6.B16
+	VCMEQ V20.B16, V3.B16, V7.B16
+	MOVEMASK(40)
+
+	RET
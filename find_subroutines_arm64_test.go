@@ -0,0 +1,132 @@
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
+
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"testing"
+)
+
+func TestFindNewlineDelimiters(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindNewlineDelimiters(t, find_newline_delimiters_neon)
+	})
+}
+
+func TestExcludeNewlineDelimitersWithinQuotes(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testExcludeNewlineDelimitersWithinQuotes(t, find_newline_delimiters_neon)
+	})
+}
+
+func TestFindOddBackslashSequences(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindOddBackslashSequences(t, find_odd_backslash_sequences_neon)
+	})
+}
+
+func TestFindQuoteMaskAndBits(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindQuoteMaskAndBits(t, find_quote_mask_and_bits_neon)
+	})
+}
+
+func TestFindStructuralBits(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindStructuralBits(t, find_structural_bits_neon)
+	})
+}
+
+func TestFindStructuralBitsWhitespacePadding(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindStructuralBitsWhitespacePadding(t, find_structural_bits_in_slice_neon)
+	})
+}
+
+func TestFindStructuralBitsLoop(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindStructuralBitsLoop(t, find_structural_bits_in_slice_neon)
+	})
+}
+
+func TestFindWhitespaceAndStructurals(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindWhitespaceAndStructurals(t, find_whitespace_and_structurals_neon)
+	})
+}
+
+func TestFindStructuralBitsInSliceGeneric(t *testing.T) {
+	if !SupportedCPU() {
+		t.SkipNow()
+	}
+	t.Run("neon", func(t *testing.T) {
+		testFindStructuralBitsInSliceGeneric(t, find_structural_bits_in_slice_neon)
+	})
+}
+
+func BenchmarkFindStructuralBits(b *testing.B) {
+	if !SupportedCPU() {
+		b.SkipNow()
+	}
+	b.Run("neon", func(b *testing.B) {
+		benchmarkFindStructuralBits(b, find_structural_bits_neon)
+	})
+}
+
+func BenchmarkFindStructuralBitsLoop(b *testing.B) {
+	if !SupportedCPU() {
+		b.SkipNow()
+	}
+	b.Run("neon", func(b *testing.B) {
+		benchmarkFindStructuralBitsLoop(b, find_structural_bits_in_slice_neon)
+	})
+}
+
+func BenchmarkFindStructuralBitsParallelLoop(b *testing.B) {
+	if !SupportedCPU() {
+		b.SkipNow()
+	}
+	b.Run("neon", func(b *testing.B) {
+		benchmarkFindStructuralBitsParallelLoop(b, find_structural_bits_in_slice_neon)
+	})
+}
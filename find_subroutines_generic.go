@@ -0,0 +1,287 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"math/bits"
+)
+
+// Pure Go versions of the stage 1 routines.
+// They operate on 64 byte blocks and produce the same masks as the assembly versions,
+// so the same tests apply to both.
+
+// Character classes used to build the masks.
+const (
+	classBackslash = 1 << iota
+	classQuote
+	classControl
+	classWhitespace
+	classStructural
+	classNewline
+)
+
+var charClass = [256]uint8{
+	'\\': classBackslash,
+	'"':  classQuote,
+	' ':  classWhitespace,
+	'\t': classWhitespace | classControl,
+	'\n': classWhitespace | classControl | classNewline,
+	'\r': classWhitespace | classControl,
+	'{':  classStructural,
+	'}':  classStructural,
+	'[':  classStructural,
+	']':  classStructural,
+	':':  classStructural,
+	',':  classStructural,
+	// Remaining control characters will be added in init below.
+}
+
+func init() {
+	for i := range charClass[:0x20] {
+		charClass[i] |= classControl
+	}
+}
+
+// blockMasks contains a mask for each character class in a 64 byte block.
+type blockMasks struct {
+	backslash  uint64
+	quote      uint64
+	control    uint64
+	whitespace uint64
+	structural uint64
+	newline    uint64
+}
+
+// classify_block_generic fills m with the character classes
+// of the first 64 bytes of buf.
+func classify_block_generic(buf []byte, m *blockMasks) {
+	buf = buf[:64]
+	var bs_bits, quotes, controls, whitespace, structurals, newlines uint64
+	for i := len(buf) - 1; i >= 0; i-- {
+		c := charClass[buf[i]]
+		bs_bits = bs_bits<<1 | uint64(c&classBackslash)
+		quotes = quotes<<1 | uint64(c&classQuote)>>1
+		controls = controls<<1 | uint64(c&classControl)>>2
+		whitespace = whitespace<<1 | uint64(c&classWhitespace)>>3
+		structurals = structurals<<1 | uint64(c&classStructural)>>4
+		newlines = newlines<<1 | uint64(c&classNewline)>>5
+	}
+	*m = blockMasks{
+		backslash:  bs_bits,
+		quote:      quotes,
+		control:    controls,
+		whitespace: whitespace,
+		structural: structurals,
+		newline:    newlines,
+	}
+}
+
+// prefixXor computes the running xor of all bits,
+// which is equivalent to carry-less multiplication by all ones.
+func prefixXor(x uint64) uint64 {
+	x ^= x << 1
+	x ^= x << 2
+	x ^= x << 4
+	x ^= x << 8
+	x ^= x << 16
+	x ^= x << 32
+	return x
+}
+
+func find_odd_backslash_sequences_generic(buf []byte, prev_iter_ends_odd_backslash *uint64) uint64 {
+	var m blockMasks
+	classify_block_generic(buf, &m)
+	return odd_backslash_sequences(m.backslash, prev_iter_ends_odd_backslash)
+}
+
+func odd_backslash_sequences(bs_bits uint64, prev_iter_ends_odd_backslash *uint64) uint64 {
+	const even_bits = 0x5555555555555555
+	const odd_bits = ^uint64(even_bits)
+
+	start_edges := bs_bits &^ (bs_bits << 1)
+	// flip lowest if we have an odd-length run at the end of the prior iteration
+	even_start_mask := even_bits ^ *prev_iter_ends_odd_backslash
+	even_starts := start_edges & even_start_mask
+	odd_starts := start_edges &^ even_start_mask
+	even_carries := bs_bits + even_starts
+
+	// must record the carry-out of our odd-carries out of bit 63; this
+	// indicates whether the sense of any edge going to the next iteration
+	// should be flipped
+	odd_carries, iter_ends_odd_backslash := bits.Add64(bs_bits, odd_starts, 0)
+
+	// push in bit zero as a potential end if we had an odd-numbered run at the end of the previous iteration
+	odd_carries |= *prev_iter_ends_odd_backslash
+	*prev_iter_ends_odd_backslash = iter_ends_odd_backslash
+
+	even_carry_ends := even_carries &^ bs_bits
+	odd_carry_ends := odd_carries &^ bs_bits
+	even_start_odd_end := even_carry_ends & odd_bits
+	odd_start_even_end := odd_carry_ends & even_bits
+	return even_start_odd_end | odd_start_even_end
+}
+
+func find_quote_mask_and_bits_generic(buf []byte, odd_ends uint64, prev_iter_inside_quote, quote_bits, error_mask *uint64) (quote_mask uint64) {
+	var m blockMasks
+	classify_block_generic(buf, &m)
+	return quote_mask_and_bits(m.quote, m.control, odd_ends, prev_iter_inside_quote, quote_bits, error_mask)
+}
+
+func quote_mask_and_bits(quotes, controls, odd_ends uint64, prev_iter_inside_quote, quote_bits, error_mask *uint64) (quote_mask uint64) {
+	*quote_bits = quotes &^ odd_ends
+	quote_mask = prefixXor(*quote_bits) ^ *prev_iter_inside_quote
+
+	// All unescaped characters (< 0x20) within quotes are errors.
+	*error_mask |= quote_mask & controls
+
+	// right shift of a signed value expected to be well-defined and standard
+	// compliant as of C++20, John Regher from Utah U. says this is fine code
+	*prev_iter_inside_quote = uint64(int64(quote_mask) >> 63)
+	return quote_mask
+}
+
+func find_whitespace_and_structurals_generic(buf []byte, whitespace, structurals *uint64) {
+	var m blockMasks
+	classify_block_generic(buf, &m)
+	*whitespace = m.whitespace
+	*structurals = m.structural
+}
+
+func finalize_structurals_generic(structurals, whitespace, quote_mask, quote_bits uint64, prev_iter_ends_pseudo_pred *uint64) uint64 {
+	// mask off anything inside quotes
+	structurals &^= quote_mask
+
+	// add the real quote bits back into our bitmask as well, so we can
+	// quickly traverse the strings we've spent all this trouble gathering
+	structurals |= quote_bits
+
+	// Now, establish "pseudo-structural characters". These are non-whitespace
+	// characters that are (a) outside quotes and (b) have a predecessor that's
+	// either whitespace or a structural character. This means that subsequent
+	// passes will get a chance to encounter the first character of every string
+	// of non-whitespace and, if we're parsing an atom like true/false/null or a
+	// number we can stop at the first whitespace or structural character
+	// following it.
+
+	// a qualified predecessor is something that can happen 1 position before an
+	// pseudo-structural character
+	pseudo_pred := structurals | whitespace
+
+	shifted_pseudo_pred := (pseudo_pred << 1) | *prev_iter_ends_pseudo_pred
+	*prev_iter_ends_pseudo_pred = pseudo_pred >> 63
+	pseudo_structurals := shifted_pseudo_pred &^ whitespace &^ quote_mask
+	structurals |= pseudo_structurals
+
+	// now, we've used our close quotes all we need to. So let's switch them off
+	// they will be off in the quote mask and on in quote bits.
+	structurals &^= quote_bits &^ quote_mask
+	return structurals
+}
+
+func find_newline_delimiters_generic(raw []byte, quoteMask uint64) (mask uint64) {
+	var m blockMasks
+	classify_block_generic(raw, &m)
+	return m.newline &^ quoteMask
+}
+
+func find_structural_bits_generic(buf []byte, prev_iter_ends_odd_backslash *uint64,
+	prev_iter_inside_quote, error_mask *uint64,
+	structurals uint64,
+	prev_iter_ends_pseudo_pred *uint64) uint64 {
+
+	quote_bits := uint64(0)
+	whitespace := uint64(0)
+
+	odd_ends := find_odd_backslash_sequences_generic(buf, prev_iter_ends_odd_backslash)
+	quote_mask := find_quote_mask_and_bits_generic(buf, odd_ends, prev_iter_inside_quote, &quote_bits, error_mask)
+	find_whitespace_and_structurals_generic(buf, &whitespace, &structurals)
+	return finalize_structurals_generic(structurals, whitespace, quote_mask, quote_bits, prev_iter_ends_pseudo_pred)
+}
+
+func find_structural_bits_in_slice_generic(buf []byte, prev_iter_ends_odd_backslash *uint64,
+	prev_iter_inside_quote, error_mask *uint64,
+	prev_iter_ends_pseudo_pred *uint64,
+	indexes *[indexSize]uint32, index *int, carried *uint64, position *uint64,
+	ndjson uint64) (processed uint64) {
+	return find_structural_bits_in_slice_blocks(false, buf, prev_iter_ends_odd_backslash,
+		prev_iter_inside_quote, error_mask,
+		prev_iter_ends_pseudo_pred,
+		indexes, index, carried, position, ndjson)
+}
+
+// find_structural_bits_in_slice_blocks implements find_structural_bits_in_slice.
+// Each 64 byte block is classified by classify_block_simd if simd is set,
+// otherwise by classify_block_generic.
+// The functions are called directly, so the state does not escape to the heap.
+func find_structural_bits_in_slice_blocks(simd bool,
+	buf []byte, prev_iter_ends_odd_backslash *uint64,
+	prev_iter_inside_quote, error_mask *uint64,
+	prev_iter_ends_pseudo_pred *uint64,
+	indexes *[indexSize]uint32, index *int, carried *uint64, position *uint64,
+	ndjson uint64) (processed uint64) {
+
+	var m blockMasks
+	var block [64]byte
+	for processed < uint64(len(buf)) && *index < indexSizeWithSafetyBuffer {
+		n := uint64(len(buf)) - processed
+		b := buf[processed:]
+		if n >= 64 {
+			n = 64
+		} else {
+			// Pad the last (partial) block with whitespace.
+			copy(block[:], b)
+			for i := n; i < uint64(len(block)); i++ {
+				block[i] = ' '
+			}
+			b = block[:]
+		}
+		if simd {
+			classify_block_simd(b, &m)
+		} else {
+			classify_block_generic(b, &m)
+		}
+
+		odd_ends := odd_backslash_sequences(m.backslash, prev_iter_ends_odd_backslash)
+		quote_bits := uint64(0)
+		quote_mask := quote_mask_and_bits(m.quote, m.control, odd_ends, prev_iter_inside_quote, &quote_bits, error_mask)
+		structurals := finalize_structurals_generic(m.structural, m.whitespace, quote_mask, quote_bits, prev_iter_ends_pseudo_pred)
+		if ndjson != 0 {
+			structurals |= m.newline &^ quote_mask
+		}
+
+		c := int(*carried)
+		flatten_bits_incremental_generic(indexes, index, structurals, &c, position)
+		*carried = uint64(c)
+		processed += n
+	}
+	return processed
+}
+
+func flatten_bits_incremental_generic(base *[indexSize]uint32, base_index *int, mask uint64, carried *int, position *uint64) {
+	shifts := 0
+	for mask != 0 {
+		zeros := bits.TrailingZeros64(mask) + 1
+		mask >>= uint(zeros)
+		shifts += zeros
+		delta := zeros + *carried
+		*carried = 0
+		base[*base_index] = uint32(delta)
+		*base_index++
+		*position += uint64(delta)
+	}
+	*carried += 64 - shifts
+}
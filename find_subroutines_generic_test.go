@@ -0,0 +1,69 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"testing"
+)
+
+func TestFinalizeStructuralsGeneric(t *testing.T) {
+	testFinalizeStructurals(t, finalize_structurals_generic)
+}
+
+func TestFindNewlineDelimitersGeneric(t *testing.T) {
+	testFindNewlineDelimiters(t, find_newline_delimiters_generic)
+}
+
+func TestExcludeNewlineDelimitersWithinQuotesGeneric(t *testing.T) {
+	testExcludeNewlineDelimitersWithinQuotes(t, find_newline_delimiters_generic)
+}
+
+func TestFindOddBackslashSequencesGeneric(t *testing.T) {
+	testFindOddBackslashSequences(t, find_odd_backslash_sequences_generic)
+}
+
+func TestFindQuoteMaskAndBitsGeneric(t *testing.T) {
+	testFindQuoteMaskAndBits(t, find_quote_mask_and_bits_generic)
+}
+
+func TestFindStructuralBitsGeneric(t *testing.T) {
+	testFindStructuralBits(t, find_structural_bits_generic)
+}
+
+func TestFindStructuralBitsWhitespacePaddingGeneric(t *testing.T) {
+	testFindStructuralBitsWhitespacePadding(t, find_structural_bits_in_slice_generic)
+}
+
+func TestFindStructuralBitsLoopGeneric(t *testing.T) {
+	testFindStructuralBitsLoop(t, find_structural_bits_in_slice_generic)
+}
+
+func TestFindWhitespaceAndStructuralsGeneric(t *testing.T) {
+	testFindWhitespaceAndStructurals(t, find_whitespace_and_structurals_generic)
+}
+
+func TestFlattenBitsIncrementalGeneric(t *testing.T) {
+	testFlattenBitsIncremental(t, flatten_bits_incremental_generic)
+}
+
+func BenchmarkFindStructuralBitsGeneric(b *testing.B) {
+	benchmarkFindStructuralBits(b, find_structural_bits_generic)
+}
+
+func BenchmarkFindStructuralBitsLoopGeneric(b *testing.B) {
+	benchmarkFindStructuralBitsLoop(b, find_structural_bits_in_slice_generic)
+}
@@ -0,0 +1,26 @@
+//go:build !arm64 || appengine || !gc || noasm
+// +build !arm64 appengine !gc noasm
+
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+// classify_block_simd is used by find_structural_bits_in_slice_blocks.
+// Only arm64 classifies blocks with SIMD, so this is never called.
+func classify_block_simd(buf []byte, m *blockMasks) {
+	classify_block_generic(buf, m)
+}
@@ -0,0 +1,157 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// followReadSize is the number of bytes a follower reads at once.
+const followReadSize = 64 << 10
+
+// follower reads input that is still being written, like tail -f.
+// It only returns complete lines and waits for more data at the end of the input.
+// A file is reopened by name when it has been replaced,
+// and read from the start when it has been truncated.
+type follower struct {
+	ctx     context.Context
+	r       io.Reader
+	poll    time.Duration
+	maxLine int // incomplete lines longer than this are returned, if > 0
+
+	buf     []byte
+	pending []byte // read, but not returned yet
+
+	file   *os.File  // r, if it is a file that can be followed by name
+	offset int64     // read position in file
+	closer io.Closer // the input if it is owned by the follower, may be nil
+}
+
+// newFollower returns a follower of r.
+// closer is closed with the follower, if not nil.
+func newFollower(ctx context.Context, r io.Reader, closer io.Closer, so streamOptions) *follower {
+	f := &follower{
+		ctx:     ctx,
+		r:       r,
+		poll:    so.follow,
+		maxLine: so.maxRecordLength,
+		closer:  closer,
+	}
+	if file, ok := r.(*os.File); ok {
+		// Pipes and terminals cannot be truncated or replaced.
+		if offset, err := file.Seek(0, io.SeekCurrent); err == nil {
+			f.file, f.offset = file, offset
+		}
+	}
+	return f
+}
+
+func (f *follower) Read(p []byte) (int, error) {
+	for {
+		end := bytes.LastIndexByte(f.pending, '\n') + 1
+		if end == 0 && f.maxLine > 0 && len(f.pending) > f.maxLine {
+			// Let the stream report the long record.
+			end = len(f.pending)
+		}
+		if end > 0 {
+			n := copy(p, f.pending[:end])
+			f.pending = f.pending[n:]
+			return n, nil
+		}
+		if err := f.fill(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// fill reads more input to pending, waiting for it at the end of the input.
+func (f *follower) fill() error {
+	// Move pending data to the start of the buffer.
+	f.buf = append(f.buf[:0], f.pending...)
+	if cap(f.buf)-len(f.buf) < followReadSize {
+		f.buf = append(make([]byte, 0, len(f.buf)+2*followReadSize), f.buf...)
+	}
+	n, err := f.r.Read(f.buf[len(f.buf):cap(f.buf)])
+	f.buf = f.buf[:len(f.buf)+n]
+	f.pending = f.buf
+	f.offset += int64(n)
+	if n > 0 {
+		return nil
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if f.file != nil {
+		if moved, err := f.reopen(); moved || err != nil {
+			return err
+		}
+	}
+	t := time.NewTimer(f.poll)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-f.ctx.Done():
+		return f.ctx.Err()
+	}
+}
+
+// reopen opens the file again if it has been replaced,
+// or reads it from the start if it has been truncated.
+// The incomplete last line of the old content is dropped.
+// It returns whether the input has moved.
+func (f *follower) reopen() (bool, error) {
+	current, err := f.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if named, err := os.Stat(f.file.Name()); err == nil && !os.SameFile(named, current) {
+		// The current file has been read to the end.
+		file, err := os.Open(f.file.Name())
+		if err != nil {
+			// It may not have been created yet.
+			return false, nil
+		}
+		if f.closer != nil {
+			f.closer.Close()
+		}
+		f.r, f.file, f.closer, f.offset = file, file, file, 0
+		f.pending = f.pending[:0]
+		return true, nil
+	}
+	if current.Size() < f.offset {
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		f.offset = 0
+		f.pending = f.pending[:0]
+		return true, nil
+	}
+	return false, nil
+}
+
+// Close closes the input if it is owned by the follower.
+func (f *follower) Close() error {
+	if f.closer != nil {
+		return f.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// followedValues returns the values and offsets of the records returned to res, one at a time.
+func followedValues(t *testing.T, res <-chan Stream) func() (interface{}, uint64) {
+	var values []interface{}
+	var offsets []uint64
+	return func() (interface{}, uint64) {
+		t.Helper()
+		for len(values) == 0 {
+			select {
+			case got, ok := <-res:
+				if !ok {
+					t.Fatal("stream closed")
+				}
+				if got.Error != nil {
+					t.Fatal(got.Error)
+				}
+				i := got.Value.Iter()
+				v, err := i.Interface()
+				if err != nil {
+					t.Fatal(err)
+				}
+				values = v.([]interface{})
+				for n := range values {
+					info, err := got.Value.RootInfo(n)
+					if err != nil {
+						t.Fatal(err)
+					}
+					offsets = append(offsets, info.Offset)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("timeout waiting for a record")
+			}
+		}
+		v, offset := values[0], offsets[0]
+		values, offsets = values[1:], offsets[1:]
+		return v, offset
+	}
+}
+
+func TestStreamFollow(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log.ndjson")
+	w, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		w.Close()
+	}()
+	write := func(s string) {
+		t.Helper()
+		if _, err := w.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("1\n2\n{\"a\":")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	res := make(chan Stream, 10)
+	ParseNDFile(ctx, name, res, nil, WithStreamFollow(time.Millisecond))
+	next := followedValues(t, res)
+	expect := func(want interface{}, wantOffset uint64) {
+		t.Helper()
+		if got, offset := next(); fmt.Sprint(got) != fmt.Sprint(want) || offset != wantOffset {
+			t.Fatalf("got %v at offset %d, want %v at offset %d", got, offset, want, wantOffset)
+		}
+	}
+	expect(int64(1), 0)
+	expect(int64(2), 2)
+
+	// The incomplete line is held back until it is written.
+	time.Sleep(20 * time.Millisecond)
+	write("3}\n4\n")
+	expect(map[string]interface{}{"a": int64(3)}, 4)
+	expect(int64(4), 12)
+
+	// Truncation drops the incomplete line, which is not counted in offsets.
+	write("{\"b\"")
+	time.Sleep(20 * time.Millisecond)
+	if err := os.Truncate(name, 0); err != nil {
+		t.Fatal(err)
+	}
+	write("5\n")
+	expect(int64(5), 14)
+
+	if runtime.GOOS != "windows" {
+		// Rotation.
+		if err := os.Rename(name, name+".1"); err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+		w, err = os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		write("6\n")
+		expect(int64(6), 16)
+	}
+
+	cancel()
+	var last Stream
+	for got := range res {
+		last = got
+	}
+	if !errors.Is(last.Error, context.Canceled) {
+		t.Errorf("got last error %v, want %v", last.Error, context.Canceled)
+	}
+}
+
+func TestNDReaderFollow(t *testing.T) {
+	pr, pw := io.Pipe()
+	r := NewNDReader(pr, WithStreamFollow(time.Millisecond))
+	defer r.Close()
+	go pw.Write([]byte("1\n2"))
+	if !r.Next() {
+		t.Fatal(r.Err())
+	}
+	if i := r.Iter(); !isInt(&i, 1) {
+		t.Error("first record: want 1")
+	}
+	go pw.Write([]byte("\n"))
+	if !r.Next() {
+		t.Fatal(r.Err())
+	}
+	if i := r.Iter(); !isInt(&i, 2) {
+		t.Error("second record: want 2")
+	}
+	// EOF of the reader does not end the stream.
+	pw.Close()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		r.cancel()
+	}()
+	if r.Next() {
+		t.Error("got a record after the end of the input")
+	}
+}
+
+func isInt(i *Iter, want int64) bool {
+	v, err := i.Int()
+	return err == nil && v == want
+}
+
+func TestStreamFollowOptions(t *testing.T) {
+	for name, opts := range map[string][]StreamOption{
+		"interval":      {WithStreamFollow(0)},
+		"decompression": {WithStreamFollow(time.Second), WithStreamDecompression()},
+	} {
+		if _, _, err := streamNDRecords(&endlessND{}, opts...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
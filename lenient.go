@@ -0,0 +1,181 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"math"
+)
+
+// utf8BOM is the UTF-8 byte order mark.
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// trimBOM removes a byte order mark at the start of b, if enabled.
+func (pj *internalParsedJson) trimBOM(b []byte) []byte {
+	if pj.lenient&LenientBOM != 0 {
+		return bytes.TrimPrefix(b, utf8BOM)
+	}
+	return b
+}
+
+// scalarStart returns whether c can start a scalar value.
+func (pj *internalParsedJson) scalarStart(c byte) bool {
+	return jsonScalarStart(c) || pj.lenient&LenientNonFinite != 0 && (c == 'N' || c == 'I')
+}
+
+// blankComments returns msg with comments replaced by whitespace, if enabled.
+// The input is only copied if it contains a comment.
+func (pj *internalParsedJson) blankComments(msg []byte) ([]byte, error) {
+	if pj.lenient&LenientComments == 0 || bytes.IndexByte(msg, '/') < 0 {
+		return msg, nil
+	}
+	start := firstComment(msg)
+	if start < 0 {
+		return msg, nil
+	}
+	msg = append([]byte(nil), msg...)
+	c := commentState{ndjson: pj.ndjson != 0, offset: uint64(start)}
+	c.blank(msg[start:], true)
+	if c.comment == '*' {
+		return nil, newParseError(msg, c.start, 1, ErrorKindUnclosedComment)
+	}
+	return msg, nil
+}
+
+// firstComment returns the offset of the first comment in msg, or -1 if there is none.
+func firstComment(msg []byte) int {
+	inString := false
+	for i := 0; i < len(msg); i++ {
+		switch msg[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case '/':
+			if !inString && i+1 < len(msg) && (msg[i+1] == '/' || msg[i+1] == '*') {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// commentState is the state of a scan for comments,
+// so input can be blanked in several parts.
+type commentState struct {
+	ndjson   bool // blank newlines in block comments, so they don't separate records
+	inString bool
+	escaped  bool
+	comment  byte   // '/' in a line comment, '*' in a block comment
+	star     bool   // the previous byte in a block comment was '*'
+	start    uint64 // offset of the current comment
+	offset   uint64 // offset of the next byte
+}
+
+// blank replaces comments in b with spaces.
+// Unless final is set, a '/' at the end of b could start a comment,
+// so it is left for the next call.
+// The number of bytes that were scanned is returned.
+func (c *commentState) blank(b []byte, final bool) int {
+	for i := 0; i < len(b); i++ {
+		ch := b[i]
+		switch {
+		case c.inString:
+			switch {
+			case c.escaped:
+				c.escaped = false
+			case ch == '\\':
+				c.escaped = true
+			case ch == '"':
+				c.inString = false
+			}
+			continue
+		case c.comment == '/':
+			if ch == '\n' {
+				c.comment = 0
+				continue
+			}
+		case c.comment == '*':
+			end := c.star && ch == '/'
+			c.star = ch == '*'
+			if end {
+				c.comment = 0
+				c.star = false
+			} else if ch == '\n' && !c.ndjson {
+				continue
+			}
+		case ch == '"':
+			c.inString = true
+			continue
+		case ch == '/':
+			if i+1 == len(b) {
+				if final {
+					// A single slash, left for the parser to reject.
+					continue
+				}
+				c.offset += uint64(i)
+				return i
+			}
+			if b[i+1] != '/' && b[i+1] != '*' {
+				continue
+			}
+			c.comment = b[i+1]
+			c.start = c.offset + uint64(i)
+			b[i] = ' '
+			i++
+		default:
+			continue
+		}
+		b[i] = ' '
+	}
+	c.offset += uint64(len(b))
+	return len(b)
+}
+
+// addNonFinite adds NaN, Infinity or -Infinity to the tape as a float, if enabled.
+func (pj *internalParsedJson) addNonFinite(buf []byte) bool {
+	f, ok := pj.nonFinite(buf)
+	if ok {
+		pj.write_tape_double(f)
+	}
+	return ok
+}
+
+// nonFinite returns the value of NaN, Infinity or -Infinity at the start of buf, if enabled.
+func (pj *internalParsedJson) nonFinite(buf []byte) (float64, bool) {
+	if pj.lenient&LenientNonFinite == 0 {
+		return 0, false
+	}
+	var f float64
+	var n int
+	switch {
+	case bytes.HasPrefix(buf, []byte("NaN")):
+		f, n = math.NaN(), 3
+	case bytes.HasPrefix(buf, []byte("Infinity")):
+		f, n = math.Inf(1), 8
+	case bytes.HasPrefix(buf, []byte("-Infinity")):
+		f, n = math.Inf(-1), 9
+	default:
+		return 0, false
+	}
+	if len(buf) > n && isNotStructuralOrWhitespace(buf[n]) != 0 {
+		return 0, false
+	}
+	return f, true
+}
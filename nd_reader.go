@@ -0,0 +1,143 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"context"
+	"io"
+)
+
+// ndReaderBlocks is the number of parsed blocks an NDReader keeps for reuse.
+const ndReaderBlocks = 4
+
+// NDReader reads the records of an NDJSON stream one at a time.
+// The stream is read ahead and parsed concurrently like ParseNDStream,
+// and the buffers of parsed blocks are reused when all their records have been read.
+//
+//	r := simdjson.NewNDReader(f)
+//	defer r.Close()
+//	for r.Next() {
+//		i := r.Iter()
+//		obj, err := i.Object(nil)
+//		...
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+//
+// An NDReader cannot be used concurrently.
+type NDReader struct {
+	cancel context.CancelFunc
+	res    chan Stream
+	reuse  chan *ParsedJson
+
+	block *ParsedJson // block containing the current record
+	roots Iter        // roots of block, at the current record
+	iter  Iter        // the current record
+	err   error
+	done  bool
+}
+
+// NewNDReader returns a reader of the records in r.
+// The options are the same as for ParseNDStreamWithOptions.
+// Close must be called to stop reading ahead, unless Next has returned false.
+func NewNDReader(r io.Reader, opts ...StreamOption) *NDReader {
+	ctx, cancel := context.WithCancel(context.Background())
+	rd := &NDReader{
+		cancel: cancel,
+		res:    make(chan Stream, ndReaderBlocks),
+		reuse:  make(chan *ParsedJson, ndReaderBlocks),
+	}
+	ParseNDStreamWithOptions(ctx, r, rd.res, rd.reuse, opts...)
+	return rd
+}
+
+// Next advances to the next record, which is then returned by Iter.
+// It returns false when there are no more records or an error occurred,
+// which is then returned by Err.
+func (r *NDReader) Next() bool {
+	for !r.done {
+		if r.block != nil {
+			if r.roots.Advance() == TypeRoot {
+				if _, _, err := r.roots.Root(&r.iter); err != nil {
+					r.stop(err)
+					return false
+				}
+				return true
+			}
+			r.recycle()
+		}
+		s, ok := <-r.res
+		switch {
+		case !ok:
+			r.stop(nil)
+		case s.Error == io.EOF:
+			r.stop(nil)
+		case s.Error != nil:
+			r.stop(s.Error)
+		default:
+			r.block = s.Value
+			r.roots = s.Value.Iter()
+		}
+	}
+	return false
+}
+
+// Iter returns an iterator of the current record, with its value queued.
+// It is only valid until the next call to Next.
+func (r *NDReader) Iter() Iter {
+	return r.iter
+}
+
+// Err returns the first error that occurred while reading or parsing.
+// It returns nil if all records have been read.
+func (r *NDReader) Err() error {
+	return r.err
+}
+
+// Close stops reading and releases the parsed blocks.
+// Records that have not been read are dropped.
+// It does not close the underlying reader.
+// A read from it that is in progress is not interrupted,
+// and Close returns when the read has returned and all goroutines have exited.
+func (r *NDReader) Close() {
+	if !r.done {
+		r.stop(nil)
+	}
+}
+
+// stop ends reading with err and waits for the stream to finish.
+func (r *NDReader) stop(err error) {
+	r.done = true
+	r.err = err
+	r.block = nil
+	r.iter = Iter{}
+	r.cancel()
+	for range r.res {
+		// Drain, so the stream can exit.
+	}
+}
+
+// recycle returns the current block for reuse.
+func (r *NDReader) recycle() {
+	select {
+	case r.reuse <- r.block:
+	default:
+	}
+	r.block = nil
+	r.roots = Iter{}
+}
@@ -0,0 +1,118 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNDReader(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "{\"id\":%d,\"tags\":[\"t%d\"]}\n", i, i%7)
+	}
+	input := sb.String()
+	pj, err := ParseND([]byte(input), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := pj.Iter()
+	want := rootJSON(t, &all)
+
+	for _, size := range []int{100, 4 << 10, 1 << 20} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			r := NewNDReader(strings.NewReader(input), WithStreamChunkSize(size))
+			defer r.Close()
+			var got []string
+			for r.Next() {
+				i := r.Iter()
+				if typ := i.Type(); typ != TypeObject {
+					t.Fatalf("record %d: got type %v", len(got), typ)
+				}
+				b, err := i.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, string(b))
+			}
+			if err := r.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("got %d records, want %d", len(got), len(want))
+			}
+			if r.Next() {
+				t.Error("Next returned true after the end")
+			}
+		})
+	}
+}
+
+func TestNDReaderError(t *testing.T) {
+	r := NewNDReader(strings.NewReader("1\n2\n[3,\n4"))
+	defer r.Close()
+	n := 0
+	for r.Next() {
+		n++
+	}
+	var perr *ParseError
+	if err := r.Err(); !errors.As(err, &perr) {
+		t.Fatalf("got error %v, want a *ParseError", err)
+	}
+	if n != 0 {
+		t.Errorf("got %d records from an invalid block", n)
+	}
+
+	r = NewNDReader(strings.NewReader("1"), WithStreamWorkers(0))
+	if r.Next() || r.Err() == nil {
+		t.Error("expected invalid options to be returned by Err")
+	}
+	r.Close()
+
+	// Skipped records are reported and reading continues.
+	var skipped []int
+	r = NewNDReader(strings.NewReader("1\n[2,\n3"), WithStreamParserOptions(WithSkipInvalidRecords(func(err *RecordError) {
+		skipped = append(skipped, err.Line)
+	})))
+	n = 0
+	for r.Next() {
+		n++
+	}
+	if r.Err() != nil || n != 2 || fmt.Sprint(skipped) != "[2]" {
+		t.Errorf("got %d records, skipped lines %v, error %v", n, skipped, r.Err())
+	}
+}
+
+func TestNDReaderClose(t *testing.T) {
+	r := NewNDReader(&endlessND{}, WithStreamChunkSize(64<<10))
+	for n := 0; n < 100000; n++ {
+		if !r.Next() {
+			t.Fatal(r.Err())
+		}
+	}
+	r.Close()
+	r.Close()
+	if r.Next() {
+		t.Error("Next returned true after Close")
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("got error %v after Close", err)
+	}
+}
@@ -34,7 +34,6 @@ const demo_ndjson = `{"Image":{"Width":800,"Height":600,"Title":"View from 15th
 {"Image":{"Width":802,"Height":602,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`
 
 func verifyDemoNdjson(pj internalParsedJson, t *testing.T, object int) {
-
 	const nul = '\000'
 
 	testCases := []struct {
@@ -248,9 +247,6 @@ func verifyDemoNdjson(pj internalParsedJson, t *testing.T, object int) {
 }
 
 func TestNdjsonCountWhere(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	if testing.Short() {
 		t.Skip("skipping... too long")
 	}
@@ -316,9 +312,6 @@ func TestNdjsonCountWhere(t *testing.T) {
 }
 
 func TestNdjsonCountWhere2(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	if testing.Short() {
 		t.Skip("skipping... too long")
 	}
@@ -409,7 +402,6 @@ func loadFile(filename string) []byte {
 }
 
 func count_raw_tape(tape []uint64) (count int) {
-
 	for tapeidx := uint64(0); tapeidx < uint64(len(tape)); count++ {
 		tape_val := tape[tapeidx]
 		tapeidx = tape_val & JSONVALUEMASK
@@ -486,10 +478,6 @@ func countObjects(data ParsedJson) (count int) {
 }
 
 func BenchmarkNdjsonWarmCountStar(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
-
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 
 	pj, err := ParseND(ndjson, nil)
@@ -506,10 +494,6 @@ func BenchmarkNdjsonWarmCountStar(b *testing.B) {
 }
 
 func BenchmarkNdjsonWarmCountStarWithWhere(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
-
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 
 	pj, err := ParseND(ndjson, nil)
@@ -0,0 +1,555 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Document is a JSON document that is parsed on demand.
+// Parsing a Document only finds and checks the structure of the input.
+// Strings, numbers and literals are parsed and validated when they are read,
+// and objects and arrays that are not read are skipped.
+//
+// Strings read from a document may point into the input or an internal buffer,
+// so values stay valid until the document is reused.
+// A Document and its values must not be used concurrently.
+type Document struct {
+	internal *internalParsedJson
+
+	// ends contains the position of the end of each object and array,
+	// indexed by the position of its start.
+	ends []uint32
+
+	// stack of open objects and arrays used when checking the structure.
+	stack []uint32
+
+	// input is the parsed input, in which the message starts at offset start.
+	// Errors are located in the input.
+	input []byte
+	start uint64
+}
+
+// ParseOnDemand parses the structure of a single JSON document.
+// The document may be an object, an array or a single scalar value.
+// Misplaced or unbalanced brackets, commas and colons are reported as a *ParseError.
+// Other invalid values are only reported when they are read.
+// An optional previously parsed document can be supplied to reduce allocations.
+//
+// WithUTF8Mode, WithMaxDepth and WithLenient apply to the whole document.
+// Number options apply when numbers are read.
+// WithDuplicateKeys and WithProjection are not supported, and keys are found in input order.
+func ParseOnDemand(b []byte, reuse *Document, opts ...ParserOption) (*Document, error) {
+	d := reuse
+	if d == nil {
+		d = &Document{}
+	}
+	if d.internal == nil {
+		d.internal = &internalParsedJson{}
+	}
+	pj := d.internal
+	if err := pj.applyOptions(opts); err != nil {
+		return nil, err
+	}
+	if pj.duplicateKeys != DuplicateKeysAllow {
+		return nil, errors.New("duplicate key policy is not supported when parsing on demand")
+	}
+	if pj.projection != nil {
+		return nil, errors.New("projection is not supported when parsing on demand")
+	}
+	pj.ndjson = 0
+	input := pj.trimBOM(b)
+	msg, err := pj.blankComments(input)
+	if err != nil {
+		return nil, locateError(err, b, uint64(len(b)-len(input)))
+	}
+	pj.Message = bytes.TrimSpace(msg)
+	d.input = b
+	d.start = uint64(len(b) - len(bytes.TrimLeftFunc(msg, unicode.IsSpace)))
+	if uint64(len(pj.Message)) > math.MaxUint32 {
+		return nil, errors.New("message too large to parse on demand")
+	}
+	pj.Tape = pj.Tape[:0]
+	if pj.Strings == nil {
+		pj.Strings = &TStrings{}
+	}
+	pj.Strings.B = pj.Strings.B[:0]
+	pj.simd = SupportedCPU()
+	pj.onDemand = true
+	pj.structurals = pj.structurals[:0]
+	pj.buffersOffset = ^uint64(0)
+	if !pj.findStructuralIndices() {
+		return nil, d.locate(pj.stage1Error())
+	}
+	if err := d.checkStructure(); err != nil {
+		return nil, locateError(err, d.input, d.start)
+	}
+	return d, nil
+}
+
+// locate returns err, found in the message, at its location in the input.
+func (d *Document) locate(err *ParseError) *ParseError {
+	return err.locate(d.input, d.start)
+}
+
+// appendStructurals adds the indices found by stage 1 to pj.structurals as message offsets.
+func (pj *internalParsedJson) appendStructurals(index indexChan) {
+	offset := ^uint32(0) // deltas start before the message, like in stage 2
+	if n := len(pj.structurals); n > 0 {
+		offset = pj.structurals[n-1]
+	}
+	for _, delta := range index.indexes[:index.length] {
+		offset += delta
+		pj.structurals = append(pj.structurals, offset)
+	}
+}
+
+// States of checkStructure.
+const (
+	structRoot = iota
+	structValue
+	structArrayFirst
+	structArrayNext
+	structObjectFirst
+	structObjectNext
+	structColon
+	structAfterValue
+)
+
+// checkStructure checks that the structural characters form a single value,
+// and records where each object and array ends.
+// Scalar values are not checked.
+func (d *Document) checkStructure() error {
+	pj := d.internal
+	msg := pj.Message
+	trailingCommas := pj.lenient&LenientTrailingCommas != 0
+	if cap(d.ends) < len(pj.structurals) {
+		d.ends = make([]uint32, len(pj.structurals))
+	}
+	ends := d.ends[:len(pj.structurals)]
+	stack := d.stack[:0]
+	state := structRoot
+	for pos, offset := range pj.structurals {
+		c := msg[offset]
+		switch state {
+		case structObjectFirst, structObjectNext:
+			if c == '}' && (state == structObjectFirst || trailingCommas) {
+				ends[stack[len(stack)-1]] = uint32(pos)
+				stack = stack[:len(stack)-1]
+				state = structAfterValue
+				break
+			}
+			if c != '"' {
+				return newParseError(msg, uint64(offset), 2, ErrorKindUnexpectedCharacter)
+			}
+			state = structColon
+		case structColon:
+			if c != ':' {
+				return newParseError(msg, uint64(offset), 2, ErrorKindUnexpectedCharacter)
+			}
+			state = structValue
+		case structAfterValue:
+			if len(stack) == 0 {
+				return newParseError(msg, uint64(offset), 2, ErrorKindTrailingData)
+			}
+			open := msg[pj.structurals[stack[len(stack)-1]]]
+			switch {
+			case c == ',' && open == '{':
+				state = structObjectNext
+			case c == ',':
+				state = structArrayNext
+			case c == '}' && open == '{', c == ']' && open == '[':
+				ends[stack[len(stack)-1]] = uint32(pos)
+				stack = stack[:len(stack)-1]
+			default:
+				return newParseError(msg, uint64(offset), 2, ErrorKindUnexpectedCharacter)
+			}
+		default:
+			// A value is expected.
+			switch {
+			case c == '"' || pj.scalarStart(c):
+				state = structAfterValue
+			case c == '{' || c == '[':
+				if pj.maxDepth > 0 && len(stack) >= pj.maxDepth {
+					return newParseError(msg, uint64(offset), 2, ErrorKindDepthExceeded)
+				}
+				stack = append(stack, uint32(pos))
+				state = structArrayFirst
+				if c == '{' {
+					state = structObjectFirst
+				}
+			case c == ']' && (state == structArrayFirst || state == structArrayNext && trailingCommas):
+				ends[stack[len(stack)-1]] = uint32(pos)
+				stack = stack[:len(stack)-1]
+				state = structAfterValue
+			default:
+				return newParseError(msg, uint64(offset), 2, ErrorKindUnexpectedCharacter)
+			}
+		}
+	}
+	d.ends, d.stack = ends, stack
+	if len(stack) > 0 || state != structAfterValue {
+		return newParseError(msg, uint64(len(msg)), 2, ErrorKindUnexpectedEnd)
+	}
+	return nil
+}
+
+// Root returns the value of the document.
+func (d *Document) Root() Value {
+	return Value{d: d}
+}
+
+// skip returns the position of the structural character after the value at pos.
+func (d *Document) skip(pos int) int {
+	pj := d.internal
+	if c := pj.Message[pj.structurals[pos]]; c == '{' || c == '[' {
+		return int(d.ends[pos]) + 1
+	}
+	return pos + 1
+}
+
+// next returns the position of the next key or element
+// after the start of a container or a value at pos.
+// If the end of the container is reached -1 is returned.
+func (d *Document) next(pos int) int {
+	msg, structurals := d.internal.Message, d.internal.structurals
+	if msg[structurals[pos]] == ',' {
+		pos++
+	}
+	if c := msg[structurals[pos]]; c == '}' || c == ']' {
+		return -1
+	}
+	return pos
+}
+
+// key returns the key at pos.
+func (d *Document) key(pos int) ([]byte, error) {
+	pj := d.internal
+	start, colon := uint64(pj.structurals[pos]), uint64(pj.structurals[pos+1])
+	if raw := bytes.TrimRight(pj.Message[start+1:colon], " \t\r\n"); len(raw) > 0 && raw[len(raw)-1] == '"' {
+		// Keys without escapes can be used as is.
+		raw = raw[:len(raw)-1]
+		if bytes.IndexByte(raw, '\\') < 0 && (pj.utf8Mode != UTF8Replace || utf8.Valid(raw)) {
+			return raw, nil
+		}
+	}
+	offset, size, ok := pj.decodeString(start, colon-start, true)
+	if !ok {
+		return nil, d.locate(newParseError(pj.Message, start, 2, ErrorKindInvalidString))
+	}
+	return pj.stringByteAt(offset, size)
+}
+
+// errNoValue is returned when reading the zero Value.
+var errNoValue = errors.New("value is not in a document")
+
+// Value is a value in a Document.
+// The value is parsed when it is read.
+// The zero Value, as returned with an error, has no value.
+type Value struct {
+	d *Document
+
+	// position of the first structural character of the value.
+	pos int
+}
+
+// first returns the first character of the value.
+func (v Value) first() byte {
+	pj := v.d.internal
+	return pj.Message[pj.structurals[v.pos]]
+}
+
+// scalar parses a scalar value and returns an iterator at the value.
+func (v Value) scalar() (Iter, error) {
+	if v.d == nil {
+		return Iter{}, errNoValue
+	}
+	pj := v.d.internal
+	idx := uint64(pj.structurals[v.pos])
+	var maxStringSize uint64
+	if v.pos+1 < len(pj.structurals) {
+		maxStringSize = uint64(pj.structurals[v.pos+1]) - idx
+	}
+	buf := pj.Message[idx:]
+	pj.Tape = pj.Tape[:0]
+	var atom [8]byte
+	kind := ErrorKindUnknown
+	switch c := buf[0]; c {
+	case '{':
+		return Iter{}, errors.New("value is object, not a scalar")
+	case '[':
+		return Iter{}, errors.New("value is array, not a scalar")
+	case '"':
+		if !parseString(pj, idx, maxStringSize, pj.copyStrings) {
+			kind = ErrorKindInvalidString
+		}
+	case 't':
+		if !isValidTrueAtom(padAtom(&atom, buf)) {
+			kind = ErrorKindInvalidLiteral
+		}
+		pj.write_tape(0, c)
+	case 'f':
+		if !isValidFalseAtom(padAtom(&atom, buf)) {
+			kind = ErrorKindInvalidLiteral
+		}
+		pj.write_tape(0, c)
+	case 'n':
+		if !isValidNullAtom(padAtom(&atom, buf)) {
+			kind = ErrorKindInvalidLiteral
+		}
+		pj.write_tape(0, c)
+	default:
+		if !addNumber(buf, pj) && !pj.addNonFinite(buf) {
+			kind = ErrorKindInvalidNumber
+			if c != '-' && (c < '0' || c > '9') {
+				kind = ErrorKindInvalidLiteral
+			}
+		}
+	}
+	if kind != ErrorKindUnknown {
+		return Iter{}, v.d.locate(newParseError(pj.Message, idx, 2, kind))
+	}
+	i := Iter{tape: pj.ParsedJson}
+	i.Advance()
+	return i, nil
+}
+
+// Type returns the type of the value.
+// Numbers and literals are parsed to find the type,
+// and TypeNone is returned if they are invalid.
+func (v Value) Type() Type {
+	if v.d == nil {
+		return TypeNone
+	}
+	switch v.first() {
+	case '{':
+		return TypeObject
+	case '[':
+		return TypeArray
+	case '"':
+		return TypeString
+	}
+	i, err := v.scalar()
+	if err != nil {
+		return TypeNone
+	}
+	return i.Type()
+}
+
+// Raw returns the JSON text of the value.
+// Values inside the text are not validated.
+// The zero Value returns nil.
+func (v Value) Raw() []byte {
+	if v.d == nil {
+		return nil
+	}
+	pj := v.d.internal
+	start := pj.structurals[v.pos]
+	end := v.d.skip(v.pos)
+	if c := pj.Message[start]; c == '{' || c == '[' {
+		return pj.Message[start : pj.structurals[end-1]+1]
+	}
+	stop := len(pj.Message)
+	if end < len(pj.structurals) {
+		stop = int(pj.structurals[end])
+	}
+	return bytes.TrimRight(pj.Message[start:stop], " \t\r\n")
+}
+
+// Bool returns the bool value.
+func (v Value) Bool() (bool, error) {
+	i, err := v.scalar()
+	if err != nil {
+		return false, err
+	}
+	return i.Bool()
+}
+
+// Int returns the value as an int64.
+// Conversion is done as by Iter.Int.
+func (v Value) Int() (int64, error) {
+	i, err := v.scalar()
+	if err != nil {
+		return 0, err
+	}
+	return i.Int()
+}
+
+// Uint returns the value as an uint64.
+// Conversion is done as by Iter.Uint.
+func (v Value) Uint() (uint64, error) {
+	i, err := v.scalar()
+	if err != nil {
+		return 0, err
+	}
+	return i.Uint()
+}
+
+// Float returns the value as a float64.
+// Conversion is done as by Iter.Float.
+func (v Value) Float() (float64, error) {
+	i, err := v.scalar()
+	if err != nil {
+		return 0, err
+	}
+	return i.Float()
+}
+
+// String returns the string value.
+func (v Value) String() (string, error) {
+	i, err := v.scalar()
+	if err != nil {
+		return "", err
+	}
+	return i.String()
+}
+
+// StringBytes returns the string value as bytes.
+// The bytes may point into the input and should not be modified.
+func (v Value) StringBytes() ([]byte, error) {
+	i, err := v.scalar()
+	if err != nil {
+		return nil, err
+	}
+	return i.StringBytes()
+}
+
+// Interface returns the value as an interface.
+// Objects are returned as map[string]interface{} and arrays as []interface{}.
+// Other values are returned as by Iter.Interface.
+func (v Value) Interface() (interface{}, error) {
+	if v.d == nil {
+		return nil, errNoValue
+	}
+	switch v.first() {
+	case '{':
+		m := make(map[string]interface{})
+		err := v.ForEach(func(key []byte, v Value) error {
+			val, err := v.Interface()
+			m[string(key)] = val
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case '[':
+		a := make([]interface{}, 0)
+		err := v.ForEachElement(func(v Value) error {
+			val, err := v.Interface()
+			a = append(a, val)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+	i, err := v.scalar()
+	if err != nil {
+		return nil, err
+	}
+	return i.Interface()
+}
+
+// ForEach calls fn with each key and value of an object in order.
+// Values that are not read by fn are skipped.
+// If fn returns an error iteration stops and the error is returned.
+func (v Value) ForEach(fn func(key []byte, v Value) error) error {
+	if v.d == nil {
+		return errNoValue
+	}
+	if v.first() != '{' {
+		return fmt.Errorf("value is not object, but %v", v.Type())
+	}
+	d := v.d
+	for pos := d.next(v.pos + 1); pos >= 0; pos = d.next(d.skip(pos + 2)) {
+		key, err := d.key(pos)
+		if err != nil {
+			return err
+		}
+		if err := fn(key, Value{d: d, pos: pos + 2}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachElement calls fn with each element of an array in order.
+// Elements that are not read by fn are skipped.
+// If fn returns an error iteration stops and the error is returned.
+func (v Value) ForEachElement(fn func(v Value) error) error {
+	if v.d == nil {
+		return errNoValue
+	}
+	if v.first() != '[' {
+		return fmt.Errorf("value is not array, but %v", v.Type())
+	}
+	d := v.d
+	for pos := d.next(v.pos + 1); pos >= 0; pos = d.next(d.skip(pos)) {
+		if err := fn(Value{d: d, pos: pos}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindKey returns the value of the first key in an object that matches key.
+// ErrPathNotFound is returned if the key cannot be found.
+func (v Value) FindKey(key string) (Value, error) {
+	if v.d == nil {
+		return Value{}, errNoValue
+	}
+	if v.first() != '{' {
+		return Value{}, fmt.Errorf("value is not object, but %v", v.Type())
+	}
+	d := v.d
+	for pos := d.next(v.pos + 1); pos >= 0; pos = d.next(d.skip(pos + 2)) {
+		k, err := d.key(pos)
+		if err != nil {
+			return Value{}, err
+		}
+		if string(k) == key {
+			return Value{d: d, pos: pos + 2}, nil
+		}
+	}
+	return Value{}, ErrPathNotFound
+}
+
+// FindElement searches for a value by path from the value,
+// moving into objects, but not arrays.
+// For example "Image", "Url" will search the object for an "Image"
+// object and return the value of the "Url" element.
+// ErrPathNotFound is returned if any part of the path cannot be found.
+func (v Value) FindElement(path ...string) (Value, error) {
+	if len(path) == 0 {
+		return Value{}, ErrPathNotFound
+	}
+	var err error
+	for _, key := range path {
+		v, err = v.FindKey(key)
+		if err != nil {
+			return Value{}, err
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,352 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOnDemand(t *testing.T) {
+	var doc *Document
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := loadCompressed(t, tt.name)
+			pj, err := Parse(ref, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			iter := pj.Iter()
+			iter.Advance()
+			_, root, err := iter.Root(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := root.Interface()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err = ParseOnDemand(ref, doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := doc.Root().Interface()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Error("on demand value does not match parsed value")
+			}
+		})
+	}
+}
+
+func TestOnDemandValue(t *testing.T) {
+	doc, err := ParseOnDemand([]byte(demo_json), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := doc.Root()
+	if root.Type() != TypeObject {
+		t.Fatalf("root type: got %v", root.Type())
+	}
+
+	url, err := root.FindElement("Image", "Thumbnail", "Url")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := url.String(); err != nil || s != "http://www.example.com/image/481989943" {
+		t.Errorf("Url: got %q, %v", s, err)
+	}
+	width, err := root.FindElement("Image", "Width")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := width.Int(); err != nil || n != 800 || width.Type() != TypeInt {
+		t.Errorf("Width: got %d, %v, %v", n, err, width.Type())
+	}
+	animated, err := root.FindElement("Image", "Animated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := animated.Bool(); err != nil || b {
+		t.Errorf("Animated: got %v, %v", b, err)
+	}
+	if _, err := root.FindElement("Image", "Missing"); err != ErrPathNotFound {
+		t.Errorf("missing key: got %v", err)
+	}
+	if _, err := root.FindElement("Image", "Width", "Value"); err == nil {
+		t.Error("expected error finding key in number")
+	}
+
+	ids, err := root.FindElement("Image", "IDs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(ids.Raw()); got != "[116,943,234,38793]" {
+		t.Errorf("Raw: got %s", got)
+	}
+	var got []uint64
+	err = ids.ForEachElement(func(v Value) error {
+		n, err := v.Uint()
+		got = append(got, n)
+		return err
+	})
+	if err != nil || !reflect.DeepEqual(got, []uint64{116, 943, 234, 38793}) {
+		t.Errorf("IDs: got %v, %v", got, err)
+	}
+
+	// Stop iteration with an error.
+	stop := errors.New("stop")
+	var keys []string
+	image, err := root.FindKey("Image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = image.ForEach(func(key []byte, v Value) error {
+		keys = append(keys, string(key))
+		if len(keys) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || strings.Join(keys, ",") != "Width,Height,Title" {
+		t.Errorf("ForEach: got %v, %v", keys, err)
+	}
+}
+
+func TestOnDemandScalars(t *testing.T) {
+	tests := []struct {
+		input string
+		want  interface{}
+		typ   Type
+		raw   string
+	}{
+		{input: `"aé\"b"`, want: "aé\"b", typ: TypeString, raw: `"aé\"b"`},
+		{input: `  -12 `, want: int64(-12), typ: TypeInt, raw: `-12`},
+		{input: `18446744073709551615`, want: uint64(18446744073709551615), typ: TypeUint, raw: `18446744073709551615`},
+		{input: `1.5e3`, want: 1500.0, typ: TypeFloat, raw: `1.5e3`},
+		{input: `true`, want: true, typ: TypeBool, raw: `true`},
+		{input: `null`, want: nil, typ: TypeNull, raw: `null`},
+		{input: `[ 1 , {"a" : [] } ]`, want: []interface{}{int64(1), map[string]interface{}{"a": []interface{}{}}}, typ: TypeArray, raw: `[ 1 , {"a" : [] } ]`},
+		{input: `{"a\n" : "b", "c":{}}`, want: map[string]interface{}{"a\n": "b", "c": map[string]interface{}{}}, typ: TypeObject, raw: `{"a\n" : "b", "c":{}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			doc, err := ParseOnDemand([]byte(tt.input), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root := doc.Root()
+			if typ := root.Type(); typ != tt.typ {
+				t.Errorf("type: got %v, want %v", typ, tt.typ)
+			}
+			if raw := string(root.Raw()); raw != tt.raw {
+				t.Errorf("raw: got %s, want %s", raw, tt.raw)
+			}
+			got, err := root.Interface()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnDemandErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		kind   ErrorKind
+		offset uint64
+	}{
+		{input: ``, kind: ErrorKindUnexpectedEnd},
+		{input: `{"a":1`, kind: ErrorKindUnexpectedEnd, offset: 6},
+		{input: `{"a" 1}`, kind: ErrorKindUnexpectedCharacter, offset: 5},
+		{input: `{"a":1,}`, kind: ErrorKindUnexpectedCharacter, offset: 7},
+		{input: `{1:1}`, kind: ErrorKindUnexpectedCharacter, offset: 1},
+		{input: `[1 2]`, kind: ErrorKindUnexpectedCharacter, offset: 3},
+		{input: `[1,]`, kind: ErrorKindUnexpectedCharacter, offset: 3},
+		{input: `[1}`, kind: ErrorKindUnexpectedCharacter, offset: 2},
+		{input: `[1] [2]`, kind: ErrorKindTrailingData, offset: 4},
+		{input: `{"a":"b}`, kind: ErrorKindUnclosedString, offset: 5},
+		{input: "\n  [1 2]", kind: ErrorKindUnexpectedCharacter, offset: 6},
+		{input: "\n\n[1] [2]", kind: ErrorKindTrailingData, offset: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseOnDemand([]byte(tt.input), nil)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			if perr.Kind != tt.kind || perr.Offset != tt.offset {
+				t.Errorf("got %v at offset %d, want %v at offset %d", perr.Kind, perr.Offset, tt.kind, tt.offset)
+			}
+		})
+	}
+
+	// Values are only validated when read.
+	doc, err := ParseOnDemand([]byte(`{"a":tru,"b":[01, "\x"],"c":1}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := doc.Root().FindKey("c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := c.Int(); err != nil || n != 1 {
+		t.Errorf("c: got %d, %v", n, err)
+	}
+	for key, want := range map[string]ErrorKind{"a": ErrorKindInvalidLiteral, "b": ErrorKindInvalidNumber} {
+		v, err := doc.Root().FindKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = v.Interface()
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Kind != want {
+			t.Errorf("%s: got %v, want %v", key, err, want)
+		}
+	}
+	// Errors found when reading values are located in the input.
+	doc, err = ParseOnDemand([]byte("\n {\"a\":\n  fase}"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := doc.Root().FindKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = v.Interface()
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Offset != 10 || perr.Line != 3 || perr.Column != 3 {
+		t.Errorf("got %v, want offset 10, line 3, column 3", err)
+	}
+	// The value returned with ErrPathNotFound can be used without panicking.
+	v, err = doc.Root().FindKey("missing")
+	if err != ErrPathNotFound {
+		t.Fatalf("got %v, want %v", err, ErrPathNotFound)
+	}
+	if v.Type() != TypeNone || v.Raw() != nil {
+		t.Errorf("got %v %q, want no value", v.Type(), v.Raw())
+	}
+	if _, err := v.Int(); err == nil {
+		t.Error("Int: want error")
+	}
+	if _, err := v.Interface(); err == nil {
+		t.Error("Interface: want error")
+	}
+	if _, err := v.FindElement("a"); err == nil {
+		t.Error("FindElement: want error")
+	}
+	if err := v.ForEachElement(func(Value) error { return nil }); err == nil {
+		t.Error("ForEachElement: want error")
+	}
+	if _, err := ParseOnDemand([]byte(`{}`), nil, WithDuplicateKeys(DuplicateKeysReject)); err == nil {
+		t.Error("expected error for unsupported option")
+	}
+}
+
+func TestOnDemandOptions(t *testing.T) {
+	input := "\xef\xbb\xbf{\"a\": [1, 2, NaN,], // comment\n \"b\": 12345678901234567890123}"
+	if _, err := ParseOnDemand([]byte(input), nil); err == nil {
+		t.Fatal("expected error without lenient parsing")
+	}
+	doc, err := ParseOnDemand([]byte(input), nil, WithLenient(LenientAll), WithBigNumbers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := doc.Root().FindKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := a.ForEachElement(func(Value) error { n++; return nil }); err != nil || n != 3 {
+		t.Errorf("elements: got %d, %v", n, err)
+	}
+	b, err := doc.Root().FindKey("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Type() != TypeNumber {
+		t.Errorf("big number type: got %v", b.Type())
+	}
+	if v, err := b.Interface(); err != nil || v != json.Number("12345678901234567890123") {
+		t.Errorf("big number: got %v, %v", v, err)
+	}
+
+	deep := strings.Repeat("[", 10) + strings.Repeat("]", 10)
+	_, err = ParseOnDemand([]byte(deep), nil, WithMaxDepth(5))
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Kind != ErrorKindDepthExceeded || perr.Offset != 5 {
+		t.Errorf("max depth: got %v", err)
+	}
+	_, err = ParseOnDemand([]byte("[\"\xff\"]"), nil, WithUTF8Mode(UTF8Reject))
+	if !errors.As(err, &perr) || perr.Kind != ErrorKindInvalidUTF8 {
+		t.Errorf("invalid UTF-8: got %v", err)
+	}
+}
+
+func BenchmarkParseOnDemand(b *testing.B) {
+	ref := loadCompressed(b, "twitter")
+	b.Run("on-demand", func(b *testing.B) {
+		b.SetBytes(int64(len(ref)))
+		b.ReportAllocs()
+		var doc *Document
+		for i := 0; i < b.N; i++ {
+			var err error
+			doc, err = ParseOnDemand(ref, doc)
+			if err != nil {
+				b.Fatal(err)
+			}
+			v, err := doc.Root().FindElement("search_metadata", "count")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := v.Int(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("tape", func(b *testing.B) {
+		b.SetBytes(int64(len(ref)))
+		b.ReportAllocs()
+		var pj *ParsedJson
+		var elem *Element
+		for i := 0; i < b.N; i++ {
+			var err error
+			pj, err = Parse(ref, pj)
+			if err != nil {
+				b.Fatal(err)
+			}
+			iter := pj.Iter()
+			elem, err = iter.FindElement(elem, "search_metadata", "count")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := elem.Iter.Int(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -1,8 +1,33 @@
 package simdjson
 
+import (
+	"fmt"
+)
+
 // ParserOption is a parser option.
 type ParserOption func(pj *internalParsedJson) error
 
+// applyOptions resets all options to their defaults and applies opts.
+func (pj *internalParsedJson) applyOptions(opts []ParserOption) error {
+	pj.copyStrings = true
+	pj.maxDepth = 0
+	pj.utf8Mode = UTF8PassThrough
+	pj.lenient = 0
+	pj.duplicateKeys = DuplicateKeysAllow
+	pj.bigNumbers = false
+	pj.decimals = false
+	pj.numbersAsText = false
+	pj.projection = nil
+	pj.skipInvalid = false
+	pj.skipReport = nil
+	for _, opt := range opts {
+		if err := opt(pj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // WithCopyStrings will copy strings so they no longer reference the input.
 // For enhanced performance, simdjson-go can point back into the original JSON buffer for strings,
 // however this can lead to issues in streaming use cases scenarios, or scenarios in which
@@ -16,3 +41,191 @@ func WithCopyStrings(b bool) ParserOption {
 		return nil
 	}
 }
+
+// WithMaxDepth will limit the nesting depth of objects and arrays to n.
+// Input nested deeper will fail with a *ParseError of kind ErrorKindDepthExceeded.
+// A value <= 0 will remove the limit.
+// Default: no limit.
+func WithMaxDepth(n int) ParserOption {
+	return func(pj *internalParsedJson) error {
+		pj.maxDepth = n
+		return nil
+	}
+}
+
+// UTF8Mode controls how invalid UTF-8 in the input is handled.
+type UTF8Mode uint8
+
+const (
+	// UTF8PassThrough does not validate the input.
+	// Invalid UTF-8 in strings is kept as is.
+	UTF8PassThrough UTF8Mode = iota
+
+	// UTF8Reject validates the input in stage 1.
+	// Invalid input will fail with a *ParseError of kind ErrorKindInvalidUTF8
+	// with the offset of the first invalid sequence.
+	UTF8Reject
+
+	// UTF8Replace replaces each byte in a string that is not part of a valid UTF-8 sequence
+	// with the replacement character U+FFFD, like encoding/json.
+	// Strings containing invalid UTF-8 are always copied.
+	// Unpaired surrogates from \u escapes are replaced as well.
+	UTF8Replace
+)
+
+// WithUTF8Mode sets how invalid UTF-8 in the input is handled.
+// Default: UTF8PassThrough.
+func WithUTF8Mode(mode UTF8Mode) ParserOption {
+	return func(pj *internalParsedJson) error {
+		if mode > UTF8Replace {
+			return fmt.Errorf("unknown UTF8Mode: %d", mode)
+		}
+		pj.utf8Mode = mode
+		return nil
+	}
+}
+
+// LenientFlags are extensions to the JSON syntax that can be enabled with WithLenient.
+type LenientFlags uint8
+
+const (
+	// LenientComments allows // line comments and /* block comments */,
+	// which are treated as whitespace.
+	// With ParseNDStream, block comments should not span lines,
+	// since the input is split into chunks at newlines.
+	LenientComments LenientFlags = 1 << iota
+
+	// LenientTrailingCommas allows a comma after the last element of an object or array.
+	LenientTrailingCommas
+
+	// LenientBOM skips a UTF-8 byte order mark at the start of the input.
+	LenientBOM
+
+	// LenientNonFinite allows the values NaN, Infinity and -Infinity.
+	// They are stored as TagFloat.
+	LenientNonFinite
+
+	// LenientAll enables all extensions.
+	LenientAll = LenientComments | LenientTrailingCommas | LenientBOM | LenientNonFinite
+)
+
+// WithLenient enables extensions to the JSON syntax.
+// Each extension is enabled separately, so flags can be combined.
+// Note that MarshalJSON writes non-finite floats as null to produce valid JSON.
+// Default: no extensions are enabled.
+func WithLenient(flags LenientFlags) ParserOption {
+	return func(pj *internalParsedJson) error {
+		if flags&^LenientAll != 0 {
+			return fmt.Errorf("unknown LenientFlags: %d", flags)
+		}
+		pj.lenient = flags
+		return nil
+	}
+}
+
+// DuplicateKeys controls how objects with more than one key with the same name are handled.
+type DuplicateKeys uint8
+
+const (
+	// DuplicateKeysAllow keeps all keys.
+	// Object.FindKey will return the first match, while Object.Map will keep the last value.
+	DuplicateKeysAllow DuplicateKeys = iota
+
+	// DuplicateKeysReject fails parsing with a *ParseError of kind ErrorKindDuplicateKey
+	// with the offset and name of the first duplicate key.
+	DuplicateKeysReject
+
+	// DuplicateKeysLastWins removes all but the last of each duplicated key from the tape,
+	// so all accessors will see the last value.
+	// Keys keep the position of their last occurrence.
+	DuplicateKeysLastWins
+)
+
+// WithDuplicateKeys sets how duplicate object keys are handled.
+// Keys are compared after unescaping.
+// Default: DuplicateKeysAllow.
+func WithDuplicateKeys(policy DuplicateKeys) ParserOption {
+	return func(pj *internalParsedJson) error {
+		if policy > DuplicateKeysLastWins {
+			return fmt.Errorf("unknown DuplicateKeys: %d", policy)
+		}
+		pj.duplicateKeys = policy
+		return nil
+	}
+}
+
+// WithBigNumbers will store numbers that cannot be represented exactly on the tape as text.
+// This applies to integers that overflow both int64 and uint64,
+// numbers outside the float64 range, which are otherwise rejected,
+// and floats with more precision than a float64 can hold.
+// These numbers get TypeNumber and can be read with Iter.BigInt, Iter.BigFloat or Iter.NumberText,
+// as well as converted by Float, Int and Uint.
+// MarshalJSON will write them exactly as they appeared in the input.
+// Default: Disabled.
+func WithBigNumbers() ParserOption {
+	return func(pj *internalParsedJson) error {
+		pj.bigNumbers = true
+		return nil
+	}
+}
+
+// WithDecimals will store numbers in float notation as exact decimals,
+// a mantissa and a base 10 exponent, when the digits fit in an int64.
+// Other numbers are stored as usual.
+// Decimals can be read exactly with Iter.Decimal and Array.AsDecimal,
+// have TypeFloat and are converted to the closest float64 by Float.
+// MarshalJSON and Serializer keep all digits.
+// Default: Disabled.
+func WithDecimals() ParserOption {
+	return func(pj *internalParsedJson) error {
+		pj.decimals = true
+		return nil
+	}
+}
+
+// WithNumbersAsText will store all numbers as their original text with TypeNumber,
+// like json.Decoder.UseNumber, so numbers are only validated when parsing.
+// Iter.Int, Iter.Uint and Iter.Float convert the text when called,
+// and Iter.Interface returns a json.Number.
+// This takes precedence over WithBigNumbers and WithDecimals.
+// Default: Disabled.
+func WithNumbersAsText() ParserOption {
+	return func(pj *internalParsedJson) error {
+		pj.numbersAsText = true
+		return nil
+	}
+}
+
+// WithProjection will only keep the values at the supplied paths of object keys on the tape.
+// Objects containing the paths are kept with only the matching keys,
+// and arrays apply the projection to all their elements.
+// For NDJSON the paths apply to each record.
+// Values that are not kept are still validated, but are not added to the tape or the string buffer.
+// Objects inside values that are not kept are not checked for duplicate keys.
+// For example WithProjection([]string{"user", "id"}, []string{"time"}) will reduce
+// {"user":{"id":1,"name":"a"},"time":2,"msg":"b"} to {"user":{"id":1},"time":2}.
+// Default: all values are kept.
+func WithProjection(paths ...[]string) ParserOption {
+	p := newProjection(paths)
+	return func(pj *internalParsedJson) error {
+		pj.projection = p
+		return nil
+	}
+}
+
+// WithSkipInvalidRecords will skip invalid records of newline delimited JSON,
+// instead of failing the whole input.
+// The valid records are parsed as if the invalid records were not there.
+// If report is non-nil, it is called in order with each skipped record.
+// This applies to ParseND, Parser.ParseND and ParseNDStream and its variants.
+// When streaming, report is called from a single goroutine,
+// before the result with the records that follow is returned.
+// Input with invalid records is parsed again in parts to find them, so it is slower to parse.
+// Default: invalid records fail the input.
+func WithSkipInvalidRecords(report func(err *RecordError)) ParserOption {
+	return func(pj *internalParsedJson) error {
+		pj.skipInvalid = true
+		pj.skipReport = report
+		return nil
+	}
+}
@@ -0,0 +1,916 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWithMaxDepth(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat(`{"a":[`, depth/2) + strings.Repeat("[", depth%2) + "1" + strings.Repeat("]", depth%2) + strings.Repeat("]}", depth/2)
+	}
+	wantDepthErr := func(t *testing.T, err error, offset uint64) {
+		t.Helper()
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("want *ParseError, got %T: %v", err, err)
+		}
+		if perr.Kind != ErrorKindDepthExceeded || perr.Offset != offset {
+			t.Fatalf("got %v, want %v at offset %d", perr, ErrorKindDepthExceeded, offset)
+		}
+	}
+
+	for _, depth := range []int{1, 2, 3, 10, 127, 128, 129, 1000} {
+		js := []byte(nested(depth))
+		// No limit by default.
+		if _, err := Parse(js, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Parse(js, nil, WithMaxDepth(depth)); err != nil {
+			t.Fatalf("depth %d: %v", depth, err)
+		}
+		_, err := Parse(js, nil, WithMaxDepth(depth-1))
+		if depth == 1 {
+			// 0 is no limit.
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		wantDepthErr(t, err, uint64(bytes.LastIndexAny(js, "{[")))
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		js := []byte(nested(4) + "\n" + nested(5) + "\n" + nested(3))
+		if _, err := ParseND(js, nil, WithMaxDepth(5)); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ParseND(js, nil, WithMaxDepth(4))
+		wantDepthErr(t, err, uint64(len(nested(4))+1+bytes.LastIndexAny([]byte(nested(5)), "{[")))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		js := nested(4) + "\n" + nested(5) + "\n"
+		res := make(chan Stream, 10)
+		ParseNDStream(strings.NewReader(js), res, nil, WithMaxDepth(5))
+		for r := range res {
+			if r.Error != nil && r.Error != io.EOF {
+				t.Fatal(r.Error)
+			}
+		}
+
+		res = make(chan Stream, 10)
+		ParseNDStream(strings.NewReader(js), res, nil, WithMaxDepth(4))
+		r := <-res
+		var perr *ParseError
+		if !errors.As(r.Error, &perr) || perr.Kind != ErrorKindDepthExceeded {
+			t.Fatalf("got %v, want %v", r.Error, ErrorKindDepthExceeded)
+		}
+		for range res {
+		}
+	})
+}
+
+func TestWithUTF8Mode(t *testing.T) {
+	// getString returns the value of the "a" field in the first root.
+	getString := func(t *testing.T, pj *ParsedJson) string {
+		t.Helper()
+		i := pj.Iter()
+		i.AdvanceInto()
+		_, root, err := i.Root(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj, err := root.Object(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		elem := obj.FindKey("a", nil)
+		if elem == nil {
+			t.Fatal("key not found")
+		}
+		s, err := elem.Iter.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	wantUTF8Err := func(t *testing.T, err error, offset uint64) {
+		t.Helper()
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("want *ParseError, got %T: %v", err, err)
+		}
+		if perr.Kind != ErrorKindInvalidUTF8 || perr.Offset != offset || perr.Stage != 1 {
+			t.Fatalf("got %v, want %v at offset %d", perr, ErrorKindInvalidUTF8, offset)
+		}
+	}
+
+	valid := `{"a":"æøå €𝄞"}`
+	invalid := "{\"a\":\"x\xffy\xe2\x82z\xed\xa0\x80\"}"
+
+	t.Run("passthrough", func(t *testing.T) {
+		pj, err := Parse([]byte(invalid), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := getString(t, pj), invalid[6:len(invalid)-2]; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		if _, err := Parse([]byte(valid), nil, WithUTF8Mode(UTF8Reject)); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Parse([]byte(invalid), nil, WithUTF8Mode(UTF8Reject))
+		wantUTF8Err(t, err, 7)
+
+		// Multi byte sequences crossing block and chunk boundaries.
+		long := `{"a":"` + strings.Repeat("x€𝄞é", 100000) + `"}`
+		if _, err := Parse([]byte(long), nil, WithUTF8Mode(UTF8Reject)); err != nil {
+			t.Fatal(err)
+		}
+		for _, offset := range []int{100, 64<<10 - 1, len(long) - 5} {
+			js := []byte(long)
+			for js[offset]&0xc0 != 0x80 {
+				offset++
+			}
+			js[offset] = 'x'
+			// The error is reported at the start of the truncated sequence.
+			start := offset - 1
+			for js[start]&0xc0 == 0x80 {
+				start--
+			}
+			_, err := Parse(js, nil, WithUTF8Mode(UTF8Reject))
+			wantUTF8Err(t, err, uint64(start))
+		}
+		// Truncated at the end of the input.
+		_, err = Parse([]byte("{\"a\":1}\n{\"b\":\"\xf0\x9d\x84"), nil, WithUTF8Mode(UTF8Reject))
+		wantUTF8Err(t, err, 14)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		want := "x\uFFFDy\uFFFD\uFFFDz\uFFFD\uFFFD\uFFFD"
+		for _, copyStrings := range []bool{true, false} {
+			pj, err := Parse([]byte(valid), nil, WithUTF8Mode(UTF8Replace), WithCopyStrings(copyStrings))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getString(t, pj); got != valid[6:len(valid)-2] {
+				t.Fatalf("got %q, want %q", got, valid[6:len(valid)-2])
+			}
+			pj, err = Parse([]byte(invalid), nil, WithUTF8Mode(UTF8Replace), WithCopyStrings(copyStrings))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getString(t, pj); got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		}
+		// Escaped strings and keys.
+		pj, err := Parse([]byte("{\"a\":\"\\t\xff\\udc00\", \"\xc0\":1}"), nil, WithUTF8Mode(UTF8Replace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := getString(t, pj); got != "\t\uFFFD\uFFFD\uFFFD\uFFFD" {
+			t.Fatalf("got %q", got)
+		}
+		i := pj.Iter()
+		got, err := i.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(got), "\"\uFFFD\":1") {
+			t.Fatalf("key not replaced: %s", got)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		js := "{\"a\":1}\n{\"a\":\"\xff\"}\n"
+		res := make(chan Stream, 10)
+		ParseNDStream(strings.NewReader(js), res, nil, WithUTF8Mode(UTF8Reject))
+		r := <-res
+		wantUTF8Err(t, r.Error, 14)
+		for range res {
+		}
+	})
+
+	if _, err := Parse([]byte(valid), nil, WithUTF8Mode(UTF8Replace+1)); err == nil {
+		t.Fatal("want error for unknown mode")
+	}
+}
+
+func TestWithLenient(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags LenientFlags
+		js    string
+		want  string
+	}{
+		{name: "line comment", flags: LenientComments, js: "// head\n{\"a\":1, // one\n\"b\":\"//x\"}// tail", want: `{"a":1,"b":"//x"}`},
+		{name: "block comment", flags: LenientComments, js: "/* head */[1,/* two\n*/2,\"/*\\\"*/\"]/**/", want: `[1,2,"/*\"*/"]`},
+		{name: "long comments", flags: LenientComments, js: "/*" + strings.Repeat("*\n", 300) + "*/[1, //" + strings.Repeat("x", 300) + "\n2]", want: `[1,2]`},
+		{name: "comment scalar", flags: LenientComments, js: "/***/ 1 //", want: `1`},
+		{name: "trailing comma object", flags: LenientTrailingCommas, js: `{"a":[1,2,],"b":{},}`, want: `{"a":[1,2],"b":{}}`},
+		{name: "trailing comma array", flags: LenientTrailingCommas, js: `[[],[1,],]`, want: `[[],[1]]`},
+		{name: "bom", flags: LenientBOM, js: "\xef\xbb\xbf {\"a\":1}", want: `{"a":1}`},
+		{name: "non-finite", flags: LenientNonFinite, js: `{"a":NaN,"b":[Infinity,-Infinity,1]}`, want: `{"a":null,"b":[null,null,1]}`},
+		{name: "non-finite root", flags: LenientNonFinite, js: `-Infinity`, want: `null`},
+		{name: "all", flags: LenientAll, js: "\xef\xbb\xbf[NaN, /* c */ 1,] // end", want: `[null,1]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.js), nil); err == nil {
+				t.Fatal("want error without flag")
+			}
+			if _, err := Parse([]byte(tt.js), nil, WithLenient(LenientAll&^tt.flags)); err == nil {
+				t.Fatal("want error with other flags")
+			}
+			pj, err := Parse([]byte(tt.js), nil, WithLenient(tt.flags))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(toJSON(t, pj)); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+			for _, size := range []int{1, 2, 3, 5, 64} {
+				pj, err := parseReaderSize([]byte(tt.js), size, WithLenient(tt.flags))
+				if err != nil {
+					t.Fatalf("size %d: %v", size, err)
+				}
+				if got := string(toJSON(t, pj)); got != tt.want {
+					t.Fatalf("size %d: got %s, want %s", size, got, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("non-finite tape", func(t *testing.T) {
+		pj, err := Parse([]byte(`[NaN,Infinity,-Infinity]`), nil, WithLenient(LenientNonFinite))
+		if err != nil {
+			t.Fatal(err)
+		}
+		i := pj.Iter()
+		i.AdvanceInto()
+		_, root, err := i.Root(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := root.Array(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := a.AsFloat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(f) != 3 || !math.IsNaN(f[0]) || !math.IsInf(f[1], 1) || !math.IsInf(f[2], -1) {
+			t.Fatalf("got %v", f)
+		}
+	})
+
+	t.Run("no comments", func(t *testing.T) {
+		// Slashes in strings don't cause the input to be copied.
+		b := []byte(`{"url":"http://x/*y*/"}`)
+		pj, err := Parse(b, nil, WithLenient(LenientComments))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if &pj.Message[0] != &b[0] {
+			t.Fatal("input was copied")
+		}
+		if got, want := string(toJSON(t, pj)), `{"url":"http://x/*y*/"}`; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, js := range []string{`[,]`, `[1,,]`, `{,}`, `{"a":1,,}`, `[NaNa]`, `[nan]`, `[+Infinity]`, `[Inf]`} {
+			if _, err := Parse([]byte(js), nil, WithLenient(LenientAll)); err == nil {
+				t.Errorf("%s: want error", js)
+			}
+		}
+		for _, js := range []string{"[1 /* x", "[1 /* x */ /* y"} {
+			_, err := Parse([]byte(js), nil, WithLenient(LenientComments))
+			var perr *ParseError
+			if !errors.As(err, &perr) || perr.Kind != ErrorKindUnclosedComment {
+				t.Fatalf("%s: got %v, want %v", js, err, ErrorKindUnclosedComment)
+			}
+			if want := uint64(strings.LastIndex(js, "/*")); perr.Offset != want {
+				t.Fatalf("%s: got offset %d, want %d", js, perr.Offset, want)
+			}
+			for _, size := range []int{1, 3, 64} {
+				_, err := parseReaderSize([]byte(js), size, WithLenient(LenientComments))
+				var rerr *ParseError
+				if !errors.As(err, &rerr) || *rerr != *perr {
+					t.Fatalf("%s: size %d: got %v, want %v", js, size, err, perr)
+				}
+			}
+		}
+		if _, err := Parse([]byte(`[1]`), nil, WithLenient(LenientAll+1)); err == nil {
+			t.Fatal("want error for unknown flags")
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		js := "{\"a\":1} // one\n/* two\nlines */ {\"a\":NaN,}\n"
+		pj, err := ParseND([]byte(js), nil, WithLenient(LenientAll))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(toJSON(t, pj)), "{\"a\":1}\n{\"a\":null}"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("array stream", func(t *testing.T) {
+		js := "\xef\xbb\xbf[ // elements\n{\"a\":1}, /* c, ] */ NaN, \"/*\",\n]"
+		res := make(chan Stream, 10)
+		ParseArrayStream(iotest.OneByteReader(strings.NewReader(js)), res, nil, WithLenient(LenientAll))
+		var got []string
+		for s := range res {
+			if s.Error == io.EOF {
+				break
+			}
+			if s.Error != nil {
+				t.Fatal(s.Error)
+			}
+			got = append(got, string(toJSON(t, s.Value)))
+		}
+		if want := "{\"a\":1}\nnull\n\"/*\""; strings.Join(got, "\n") != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+
+		res = make(chan Stream, 10)
+		ParseArrayStream(strings.NewReader("[1, /* 2"), res, nil, WithLenient(LenientComments))
+		s := <-res
+		var perr *ParseError
+		if !errors.As(s.Error, &perr) || perr.Kind != ErrorKindUnclosedComment {
+			t.Fatalf("got %v, want %v", s.Error, ErrorKindUnclosedComment)
+		}
+		for range res {
+		}
+	})
+}
+
+func TestWithDuplicateKeys(t *testing.T) {
+	t.Run("allow", func(t *testing.T) {
+		pj, err := Parse([]byte(`{"a":1,"a":2}`), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(toJSON(t, pj)); got != `{"a":1,"a":2}` {
+			t.Fatalf("got %s", got)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		for _, js := range []string{`{"a":1,"b":{"a":2,"b":3},"c":[{"a":1},{"a":2}]}`, `{}`, `[{"a":{"a":{"a":1}}}]`} {
+			if _, err := Parse([]byte(js), nil, WithDuplicateKeys(DuplicateKeysReject)); err != nil {
+				t.Fatalf("%s: %v", js, err)
+			}
+		}
+		tests := []struct {
+			js     string
+			key    string
+			offset uint64
+		}{
+			{js: `{"a":1,"a":2}`, key: "a", offset: 7},
+			{js: `{"a":1,"b":{"x":[],"y":{},"x":2}}`, key: "x", offset: 26},
+			{js: `{"a":{"b":1},"b":{},"b":1,"b":2}`, key: "b", offset: 20},
+			{js: "{\"a\":1}\n{\"b\":1,\"b\":2}", key: "b", offset: 15},
+		}
+		for _, tt := range tests {
+			for _, copyStrings := range []bool{true, false} {
+				_, err := ParseND([]byte(tt.js), nil, WithDuplicateKeys(DuplicateKeysReject), WithCopyStrings(copyStrings))
+				var perr *ParseError
+				if !errors.As(err, &perr) {
+					t.Fatalf("%s: want *ParseError, got %v", tt.js, err)
+				}
+				if perr.Kind != ErrorKindDuplicateKey || perr.Key != tt.key || perr.Offset != tt.offset {
+					t.Fatalf("%s: got %v, want %q at offset %d", tt.js, perr, tt.key, tt.offset)
+				}
+				if want := fmt.Sprintf("duplicate key %q at offset %d", tt.key, tt.offset); !strings.HasPrefix(perr.Error(), want) {
+					t.Fatalf("got %q, want prefix %q", perr.Error(), want)
+				}
+			}
+		}
+		// Errors are located in the input when parsing from a reader.
+		js := `{"x":"` + strings.Repeat("y", 1000) + `","a":{"b":1,"b":2}}`
+		for _, size := range []int{1, 64, 4096} {
+			_, err := parseReaderSize([]byte(js), size, WithDuplicateKeys(DuplicateKeysReject))
+			var perr *ParseError
+			if !errors.As(err, &perr) || perr.Kind != ErrorKindDuplicateKey || perr.Offset != uint64(len(js)-7) {
+				t.Fatalf("size %d: got %v", size, err)
+			}
+		}
+	})
+
+	t.Run("last wins", func(t *testing.T) {
+		js := `{"a":1,"b":[{"x":1,"x":{"y":[1,"s"]}},2.5],"a":{"c":"d","c":"e"},"z":-1,"b":[{"x":true,"x":null}]}`
+		want := `{"a":{"c":"e"},"z":-1,"b":[{"x":null}]}`
+		for _, copyStrings := range []bool{true, false} {
+			pj, err := Parse([]byte(js), nil, WithDuplicateKeys(DuplicateKeysLastWins), WithCopyStrings(copyStrings))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(toJSON(t, pj)); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			i := pj.Iter()
+			i.AdvanceInto()
+			_, root, err := i.Root(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			obj, err := root.Object(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			elem := obj.FindKey("z", nil)
+			if elem == nil {
+				t.Fatal("key not found")
+			}
+			if v, err := elem.Iter.Int(); err != nil || v != -1 {
+				t.Fatalf("got %v, %v", v, err)
+			}
+		}
+		for _, size := range []int{1, 7, 4096} {
+			pj, err := parseReaderSize([]byte(js), size, WithDuplicateKeys(DuplicateKeysLastWins))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(toJSON(t, pj)); got != want {
+				t.Fatalf("size %d: got %s, want %s", size, got, want)
+			}
+		}
+		// All testdata must be unchanged.
+		for _, tt := range testCases {
+			msg := loadCompressed(t, tt.name)
+			pj, err := Parse(msg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pj2, err := Parse(msg, nil, WithDuplicateKeys(DuplicateKeysLastWins))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalUint64s(pj.Tape, pj2.Tape) {
+				t.Fatalf("%s: tape changed", tt.name)
+			}
+		}
+	})
+
+	if _, err := Parse([]byte(`{}`), nil, WithDuplicateKeys(DuplicateKeysLastWins+1)); err == nil {
+		t.Fatal("want error for unknown policy")
+	}
+}
+
+func TestWithBigNumbers(t *testing.T) {
+	tests := []struct {
+		text string
+		typ  Type
+	}{
+		{text: "123", typ: TypeInt},
+		{text: "18446744073709551615", typ: TypeUint},
+		{text: "18446744073709551616", typ: TypeNumber},
+		{text: "-9223372036854775809", typ: TypeNumber},
+		{text: "123456789012345678901234567890", typ: TypeNumber},
+		{text: "0.1", typ: TypeFloat},
+		{text: "-2.5e-3", typ: TypeFloat},
+		{text: "1.7976931348623157e308", typ: TypeFloat},
+		{text: "3.141592653589793238462643383279", typ: TypeNumber},
+		{text: "1e400", typ: TypeNumber},
+		{text: "-1E-400", typ: TypeNumber},
+	}
+	for _, tt := range tests {
+		for _, copyStrings := range []bool{true, false} {
+			js := `{"a":[` + tt.text + `],"b":` + tt.text + `}`
+			pj, err := Parse([]byte(js), nil, WithBigNumbers(), WithCopyStrings(copyStrings))
+			if err != nil {
+				t.Fatalf("%s: %v", tt.text, err)
+			}
+			if got := string(toJSON(t, pj)); tt.typ == TypeNumber && got != js {
+				t.Fatalf("got %s, want %s", got, js)
+			}
+			i := pj.Iter()
+			elem, err := i.FindElement(nil, "b")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := elem.Type; got != tt.typ {
+				t.Fatalf("%s: got type %v, want %v", tt.text, got, tt.typ)
+			}
+			if tt.typ != TypeNumber {
+				continue
+			}
+			text, err := elem.Iter.NumberText()
+			if err != nil || text != tt.text {
+				t.Fatalf("got %q, %v, want %q", text, err, tt.text)
+			}
+			v, err := elem.Iter.Interface()
+			if err != nil || v != json.Number(tt.text) {
+				t.Fatalf("got %#v, %v", v, err)
+			}
+			f, err := elem.Iter.BigFloat()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _, _ := big.ParseFloat(tt.text, 10, f.Prec(), big.ToNearestEven)
+			if f.Cmp(want) != 0 {
+				t.Fatalf("got %v, want %v", f, want)
+			}
+			if isIntegerText([]byte(tt.text)) {
+				b, err := elem.Iter.BigInt()
+				if err != nil || b.String() != tt.text {
+					t.Fatalf("got %v, %v, want %s", b, err, tt.text)
+				}
+				if _, err := elem.Iter.Int(); err == nil {
+					t.Fatal("want overflow error")
+				}
+				ff, flags, err := elem.Iter.FloatFlags()
+				if err != nil || !flags.Contains(FloatOverflowedInteger) {
+					t.Fatalf("got %v, %v, %v", ff, flags, err)
+				}
+			}
+		}
+	}
+
+	t.Run("default", func(t *testing.T) {
+		pj, err := Parse([]byte(`18446744073709551616`), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		i := pj.Iter()
+		i.AdvanceInto()
+		if typ := i.Advance(); typ != TypeFloat {
+			t.Fatalf("got %v", typ)
+		}
+		if _, err := Parse([]byte(`1e400`), nil); err == nil {
+			t.Fatal("want error")
+		}
+	})
+
+	t.Run("accessors", func(t *testing.T) {
+		pj, err := Parse([]byte(`[12345678901234567890123, 1.00000000000000000001, 1e400]`), nil, WithBigNumbers())
+		if err != nil {
+			t.Fatal(err)
+		}
+		i := pj.Iter()
+		i.AdvanceInto()
+		_, root, err := i.Root(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		arr, err := root.Array(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ai := arr.Iter()
+		var got []string
+		for ai.Advance() == TypeNumber {
+			b, err := ai.BigInt()
+			got = append(got, fmt.Sprint(b, err != nil))
+		}
+		if want := "[12345678901234567890123 false <nil> true 1" + strings.Repeat("0", 400) + " false]"; fmt.Sprint(got) != want {
+			t.Fatalf("got %v", got)
+		}
+		if _, err := arr.AsFloat(); err == nil {
+			t.Fatal("want error for 1e400")
+		}
+	})
+
+	t.Run("serialize", func(t *testing.T) {
+		js := `{"a":123456789012345678901234567890,"b":[0.1000000000000000000001,2]}`
+		pj, err := Parse([]byte(js), nil, WithBigNumbers())
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := NewSerializer()
+		pj2, err := s.Deserialize(s.Serialize(nil, *pj), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(toJSON(t, pj2)); got != js {
+			t.Fatalf("got %s, want %s", got, js)
+		}
+		for _, size := range []int{1, 64} {
+			pj, err := parseReaderSize([]byte(js), size, WithBigNumbers())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(toJSON(t, pj)); got != js {
+				t.Fatalf("size %d: got %s, want %s", size, got, js)
+			}
+		}
+	})
+}
+
+func TestWithDecimals(t *testing.T) {
+	js := `{"price":19.99,"qty":3,"rate":1.50,"big":1e300,"tiny":-1e-7,"long":0.12345678901234567890123,"list":[0.1,2,3.25]}`
+	want := `{"price":19.99,"qty":3,"rate":1.50,"big":1e300,"tiny":-0.0000001,"long":0.12345678901234568,"list":[0.1,2,3.25]}`
+	pj, err := Parse([]byte(js), nil, WithDecimals())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(toJSON(t, pj)); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	s := NewSerializer()
+	pj2, err := s.Deserialize(s.Serialize(nil, *pj), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(toJSON(t, pj2)); got != want {
+		t.Fatalf("serialized: got %s, want %s", got, want)
+	}
+
+	get := func(t *testing.T, key string) *Iter {
+		t.Helper()
+		i := pj.Iter()
+		elem, err := i.FindElement(nil, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &elem.Iter
+	}
+	decimals := []struct {
+		key      string
+		mantissa int64
+		exp      int32
+		f        float64
+	}{
+		{key: "price", mantissa: 1999, exp: -2, f: 19.99},
+		{key: "qty", mantissa: 3, exp: 0, f: 3},
+		{key: "rate", mantissa: 150, exp: -2, f: 1.5},
+		{key: "big", mantissa: 1, exp: 300, f: 1e300},
+		{key: "tiny", mantissa: -1, exp: -7, f: -1e-7},
+	}
+	for _, d := range decimals {
+		i := get(t, d.key)
+		mantissa, exp, err := i.Decimal()
+		if err != nil || mantissa != d.mantissa || exp != d.exp {
+			t.Fatalf("%s: got %d, %d, %v", d.key, mantissa, exp, err)
+		}
+		if f, err := i.Float(); err != nil || f != d.f {
+			t.Fatalf("%s: got %v, %v", d.key, f, err)
+		}
+		if d.key != "qty" && i.Type() != TypeFloat {
+			t.Fatalf("%s: got type %v", d.key, i.Type())
+		}
+	}
+	if v, err := get(t, "price").Int(); err != nil || v != 19 {
+		t.Fatalf("got %v, %v", v, err)
+	}
+	if _, err := get(t, "big").Int(); err == nil {
+		t.Fatal("want overflow error")
+	}
+	if _, _, err := get(t, "long").Decimal(); err == nil {
+		t.Fatal("want error for float")
+	}
+	if v, err := get(t, "big").BigInt(); err != nil || v.String() != "1"+strings.Repeat("0", 300) {
+		t.Fatalf("got %v, %v", v, err)
+	}
+	if _, err := get(t, "rate").BigInt(); err == nil {
+		t.Fatal("want error for fraction")
+	}
+
+	arr, err := get(t, "list").Array(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mantissas, exps, err := arr.AsDecimal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(mantissas, exps) != "[1 2 325] [-1 0 -2]" {
+		t.Fatalf("got %v %v", mantissas, exps)
+	}
+	floats, err := arr.AsFloat()
+	if err != nil || fmt.Sprint(floats) != "[0.1 2 3.25]" {
+		t.Fatalf("got %v, %v", floats, err)
+	}
+
+	// Without the option, floats are not exact.
+	pj, err = Parse([]byte(js), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := get(t, "price").Decimal(); err == nil {
+		t.Fatal("want error for float")
+	}
+}
+
+func TestWithNumbersAsText(t *testing.T) {
+	js := `{"a":1,"b":-2.5e3,"c":18446744073709551616,"d":[0,1.0,-0.0]}`
+	for _, copyStrings := range []bool{true, false} {
+		pj, err := Parse([]byte(js), nil, WithNumbersAsText(), WithCopyStrings(copyStrings), WithDecimals())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(toJSON(t, pj)); got != js {
+			t.Fatalf("got %s, want %s", got, js)
+		}
+		i := pj.Iter()
+		v, err := i.Interface()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []interface{}{map[string]interface{}{
+			"a": json.Number("1"),
+			"b": json.Number("-2.5e3"),
+			"c": json.Number("18446744073709551616"),
+			"d": []interface{}{json.Number("0"), json.Number("1.0"), json.Number("-0.0")},
+		}}
+		if fmt.Sprint(v) != fmt.Sprint(want) {
+			t.Fatalf("got %v, want %v", v, want)
+		}
+
+		i = pj.Iter()
+		elem, err := i.FindElement(nil, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if elem.Type != TypeNumber {
+			t.Fatalf("got type %v", elem.Type)
+		}
+		if v, err := elem.Iter.Int(); err != nil || v != 1 {
+			t.Fatalf("got %v, %v", v, err)
+		}
+		elem, err = i.FindElement(nil, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, err := elem.Iter.Float(); err != nil || v != -2500 {
+			t.Fatalf("got %v, %v", v, err)
+		}
+		if v, err := elem.Iter.Int(); err != nil || v != -2500 {
+			t.Fatalf("got %v, %v", v, err)
+		}
+		if _, err := elem.Iter.Uint(); err == nil {
+			t.Fatal("want error for negative")
+		}
+		elem, err = i.FindElement(nil, "c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, err := elem.Iter.Uint(); err == nil {
+			t.Fatalf("want overflow error, got %v", v)
+		}
+	}
+
+	for _, js := range []string{`[01]`, `[1.]`, `[-]`, `[1e]`, `[.5]`, `[1.5x]`, `-`, `{"a":+1}`} {
+		if _, err := Parse([]byte(js), nil, WithNumbersAsText()); err == nil {
+			t.Errorf("%s: want error", js)
+		}
+	}
+
+	// Roots and windows.
+	js = "123\n-4.5e-6\n"
+	pj, err := ParseND([]byte(js), nil, WithNumbersAsText())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(toJSON(t, pj)); got != strings.TrimSpace(js) {
+		t.Fatalf("got %q", got)
+	}
+	js = `[` + strings.Repeat(`123456789.5,`, 100) + `1]`
+	for _, size := range []int{1, 64} {
+		pj, err := parseReaderSize([]byte(js), size, WithNumbersAsText())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(toJSON(t, pj)); got != js {
+			t.Fatalf("size %d: got %s", size, got)
+		}
+	}
+}
+
+func TestWithProjection(t *testing.T) {
+	tests := []struct {
+		js    string
+		paths [][]string
+		want  string
+	}{
+		{
+			js:    `{"user":{"id":1,"name":"a"},"time":2,"msg":"b"}`,
+			paths: [][]string{{"user", "id"}, {"time"}},
+			want:  `{"user":{"id":1},"time":2}`,
+		},
+		{
+			js:    `{"a":{"b":[1,{"c":"x"}],"d":"é"},"e":[{"f":1,"g":2},{"g":3},4]}`,
+			paths: [][]string{{"a"}, {"e", "g"}, {"a", "b"}},
+			want:  `{"a":{"b":[1,{"c":"x"}],"d":"é"},"e":[{"g":2},{"g":3},4]}`,
+		},
+		{
+			js:    `[{"a":1,"b":2},[{"b":3,"c":{"b":4}}]]`,
+			paths: [][]string{{"b"}},
+			want:  `[{"b":2},[{"b":3}]]`,
+		},
+		{
+			js:    `{"a":1,"b":2}`,
+			paths: nil,
+			want:  `{}`,
+		},
+		{
+			js:    `{"a":1,"b":2}`,
+			paths: [][]string{{"x"}, {}},
+			want:  `{"a":1,"b":2}`,
+		},
+		{
+			js:    `"text"`,
+			paths: [][]string{{"a"}},
+			want:  `"text"`,
+		},
+	}
+	for _, tt := range tests {
+		for _, copyStrings := range []bool{true, false} {
+			pj, err := Parse([]byte(tt.js), nil, WithProjection(tt.paths...), WithCopyStrings(copyStrings))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(toJSON(t, pj)); got != tt.want {
+				t.Errorf("%s %v: got %s, want %s", tt.js, tt.paths, got, tt.want)
+			}
+		}
+	}
+
+	// Values that are not kept are still validated, with the same errors.
+	for _, js := range []string{`{"a":1,"b":[1,tru]}`, `{"b":{"c":"\x"},"a":1}`, `{"b":01}`, `{"b":[1 2]}`,
+		`{"b":{"c" 1}}`, `{"b":{"c":1,}}`, `{"b":[[[[1]]]]}`, `{"b":{"c":[1,{"d":`, `{"b":x}`} {
+		_, want := Parse([]byte(js), nil, WithMaxDepth(4))
+		_, err := Parse([]byte(js), nil, WithProjection([]string{"a"}), WithMaxDepth(4))
+		var perr, wantErr *ParseError
+		if !errors.As(err, &perr) || !errors.As(want, &wantErr) || *perr != *wantErr {
+			t.Errorf("%s: got %v, want %v", js, err, want)
+		}
+	}
+	pj, err := Parse([]byte(`{"a":1,"b":{"c":[1,NaN,],}}`), nil, WithProjection([]string{"a"}), WithLenient(LenientAll))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(toJSON(t, pj)); got != `{"a":1}` {
+		t.Errorf("lenient: got %s", got)
+	}
+
+	// The strings of values that are not kept are not stored.
+	js := `{"id":1,"payload":"` + strings.Repeat("x", 10000) + `","tags":["` + strings.Repeat("y", 1000) + `"]}`
+	full, err := Parse([]byte(js), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pj, err = Parse([]byte(js), nil, WithProjection([]string{"id"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(toJSON(t, pj)); got != `{"id":1}` {
+		t.Errorf("got %s", got)
+	}
+	if len(pj.Strings.B) != len("id") || len(pj.Tape) >= len(full.Tape) {
+		t.Errorf("tape %d, strings %d", len(pj.Tape), len(pj.Strings.B))
+	}
+
+	// The projection applies to each record and works with other options.
+	nd := "{\"a\":1,\"b\":2,\"b\":3}\n{\"b\":{\"c\":4},\"a\":[5]}\n{\"c\":6}"
+	pj, err = ParseND([]byte(nd), nil, WithProjection([]string{"b"}), WithDuplicateKeys(DuplicateKeysLastWins))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(toJSON(t, pj)); got != "{\"b\":3}\n{\"b\":{\"c\":4}}\n{}" {
+		t.Errorf("got %s", got)
+	}
+	for _, size := range []int{1, 64, 4096} {
+		pj, err := parseReaderSize([]byte(js), size, WithProjection([]string{"tags"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(toJSON(t, pj)), `{"tags":["`+strings.Repeat("y", 1000)+`"]}`; got != want {
+			t.Errorf("size %d: got %s", size, got)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ParseArrayStream will parse a stream containing a single JSON array
+// and return the elements of the array to the supplied result channel.
+// The method will return immediately.
+// Each element is contained within a root tag, like ParseNDStream.
+//   <root>Element 1</root><root>Element 2</root>...
+// Each result will contain an unspecified number of full elements,
+// so memory use is bounded by the block size and the largest element,
+// not by the length of the array.
+// Blocks are parsed concurrently and results are returned in order.
+// See ParseNDStream for how errors, io.EOF and the reuse channel are handled.
+// Parser options are applied to each parsed block.
+func ParseArrayStream(r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...ParserOption) {
+	a := &arraySplitter{r: r, size: streamChunkSize, line: 1}
+	if pj, err := newInternalParsedJson(nil, opts); err == nil {
+		// Invalid options are reported by parseStream.
+		a.lenient = pj.lenient
+	}
+	so := streamParserOptions(opts)
+	so.locate = a.locator
+	parseStream(context.Background(), res, reuse, so, separatorNewline, a.read)
+}
+
+// arrayReadSize is the number of bytes ParseArrayStream reads at once.
+const arrayReadSize = 64 << 10
+
+// arraySplitter converts a JSON array to newline delimited elements.
+// Newlines outside strings are replaced by spaces
+// and the commas separating the elements are replaced by newlines.
+// Elements are not validated, that is left to the parser.
+type arraySplitter struct {
+	r       io.Reader
+	size    int // minimum size of a block
+	err     error
+	buf     []byte
+	pending []byte // input read, but not converted yet
+
+	lenient  LenientFlags
+	comments commentState
+	slash    bool // a '/' at the end of the previous read is held back
+
+	state    arrayState
+	depth    int
+	inString bool
+	escaped  bool
+	element  bool // an element has been seen after the last separator
+	comma    bool // a separator has been seen
+	values   bool // the current block contains elements
+
+	// location of pending[0] in the input
+	offset    uint64
+	line      int
+	lineStart uint64
+
+	// location of the block being read in the input
+	blockOffset    uint64
+	blockLine      int
+	blockLineStart uint64
+	newlines       []int // offsets in the block of the newlines in the input
+}
+
+type arrayState uint8
+
+const (
+	arrayBefore arrayState = iota
+	arrayInside
+	arrayAfter
+)
+
+// read appends full elements to tmp until it is at least a.size bytes
+// or the end of the input is reached.
+func (a *arraySplitter) read(tmp []byte) ([]byte, error) {
+	a.values = false
+	a.newlines = nil
+	for {
+		if len(a.pending) > 0 {
+			start := len(tmp)
+			if start == 0 {
+				a.blockOffset, a.blockLine, a.blockLineStart = a.offset, a.line, a.lineStart
+			}
+			tmp = append(tmp, a.pending...)
+			n, err := a.convert(tmp[start:], start)
+			if err != nil {
+				return nil, err
+			}
+			a.offset += uint64(n)
+			a.pending = a.pending[n:]
+			tmp = tmp[:start+n]
+			if len(a.pending) > 0 {
+				// The block is full.
+				return tmp, nil
+			}
+		}
+		if a.err != nil {
+			if a.err == io.EOF && a.comments.comment == '*' {
+				// Reported at the end of the input, where the line is known.
+				return nil, a.error(a.offset, ErrorKindUnclosedComment)
+			}
+			if a.err == io.EOF && a.state != arrayAfter {
+				return nil, a.error(a.offset, ErrorKindUnexpectedEnd)
+			}
+			if !a.values {
+				// Don't return blocks with only whitespace.
+				tmp = tmp[:0]
+			}
+			return tmp, a.err
+		}
+		if a.buf == nil {
+			a.buf = make([]byte, arrayReadSize)
+		}
+		a.pending = a.fill()
+	}
+}
+
+// fill reads the next input and applies the lenient flags to it.
+func (a *arraySplitter) fill() []byte {
+	b := a.buf
+	if a.slash {
+		b[0] = '/'
+		b = b[1:]
+	}
+	var n int
+	if a.offset == 0 && a.lenient&LenientBOM != 0 {
+		// Read enough to recognize a byte order mark.
+		n, a.err = io.ReadAtLeast(a.r, b, len(utf8BOM))
+		if a.err == io.ErrUnexpectedEOF {
+			a.err = io.EOF
+		}
+	} else {
+		n, a.err = a.r.Read(b)
+	}
+	b = a.buf[:n]
+	if a.slash {
+		b = a.buf[:n+1]
+		a.slash = false
+	}
+	if a.offset == 0 && a.lenient&LenientBOM != 0 && bytes.HasPrefix(b, utf8BOM) {
+		b = b[len(utf8BOM):]
+		a.offset = uint64(len(utf8BOM))
+	}
+	if a.lenient&LenientComments != 0 {
+		n := a.comments.blank(b, a.err != nil)
+		a.slash = n < len(b)
+		b = b[:n]
+	}
+	return b
+}
+
+// convert converts b in place and returns the number of bytes that belong to the current block.
+// size is the size of the block before b.
+func (a *arraySplitter) convert(b []byte, size int) (int, error) {
+	for i := 0; i < len(b); i++ {
+		if a.inString {
+			i = a.skipString(b, i)
+			continue
+		}
+		c := b[i]
+		switch c {
+		case ' ', '\t', '\r':
+			continue
+		case '\n':
+			b[i] = ' '
+			a.newlines = append(a.newlines, size+i)
+			a.line++
+			a.lineStart = a.offset + uint64(i) + 1
+			continue
+		}
+		switch a.state {
+		case arrayBefore:
+			if c != '[' {
+				return 0, a.error(a.offset+uint64(i), ErrorKindUnexpectedCharacter)
+			}
+			b[i] = ' '
+			a.state = arrayInside
+			a.depth = 1
+			continue
+		case arrayAfter:
+			return 0, a.error(a.offset+uint64(i), ErrorKindTrailingData)
+		}
+		switch c {
+		case '"':
+			a.inString = true
+		case '{', '[':
+			a.depth++
+		case '}', ']':
+			a.depth--
+			if a.depth > 0 {
+				break
+			}
+			if c != ']' || !a.element && a.comma && a.lenient&LenientTrailingCommas == 0 {
+				// Mismatched bracket or trailing comma.
+				return 0, a.error(a.offset+uint64(i), ErrorKindUnexpectedCharacter)
+			}
+			b[i] = ' '
+			a.state = arrayAfter
+			continue
+		case ',':
+			if a.depth > 1 {
+				break
+			}
+			if !a.element {
+				return 0, a.error(a.offset+uint64(i), ErrorKindUnexpectedCharacter)
+			}
+			b[i] = '\n'
+			a.element = false
+			a.comma = true
+			if size+i+1 >= a.size {
+				return i + 1, nil
+			}
+			continue
+		}
+		a.element = true
+		a.values = true
+	}
+	return len(b), nil
+}
+
+// skipString skips a string starting at b[i] and returns the index of the closing quote.
+// If the string doesn't end in b, len(b) is returned.
+func (a *arraySplitter) skipString(b []byte, i int) int {
+	for {
+		q := bytes.IndexByte(b[i:], '"')
+		end := i + q
+		if q < 0 {
+			end = len(b)
+		}
+		// Count the backslashes before end.
+		n := 0
+		for end-n > i && b[end-n-1] == '\\' {
+			n++
+		}
+		if end-n == i && a.escaped {
+			n++
+		}
+		a.escaped = n&1 == 1
+		if q < 0 {
+			return len(b)
+		}
+		if !a.escaped {
+			a.inString = false
+			return end
+		}
+		a.escaped = false
+		i = end + 1
+	}
+}
+
+// locator returns a function that moves an error in the last block read
+// to its location in the input.
+// Lines and columns are counted in the input,
+// since the separators and newlines have been replaced in the block.
+func (a *arraySplitter) locator() func(*ParseError) *ParseError {
+	offset, line, lineStart, newlines := a.blockOffset, a.blockLine, a.blockLineStart, a.newlines
+	return func(e *ParseError) *ParseError {
+		located := *e
+		located.Offset = offset + e.Offset
+		// Number of newlines before the error.
+		n := sort.SearchInts(newlines, int(e.Offset))
+		located.Line = line + n
+		if n > 0 {
+			located.Column = int(e.Offset) - newlines[n-1]
+		} else {
+			located.Column = int(located.Offset-lineStart) + 1
+		}
+		return &located
+	}
+}
+
+// error returns an error at offset in the input.
+func (a *arraySplitter) error(offset uint64, kind ErrorKind) error {
+	err := &ParseError{
+		Offset: offset,
+		Line:   a.line,
+		Column: int(offset-a.lineStart) + 1,
+		Stage:  1,
+		Kind:   kind,
+	}
+	return fmt.Errorf("parsing input: %w", err)
+}
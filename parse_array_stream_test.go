@@ -0,0 +1,186 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// arrayStreamElements returns the elements of the array in r as JSON,
+// using blocks of the specified size.
+func arrayStreamElements(t *testing.T, r io.Reader, size int) ([]string, error) {
+	t.Helper()
+	res := make(chan Stream, 10)
+	a := &arraySplitter{r: r, size: size, line: 1}
+	so := streamParserOptions(nil)
+	so.locate = a.locator
+	parseStream(context.Background(), res, nil, so, separatorNewline, a.read)
+	var got []string
+	for s := range res {
+		if s.Error != nil {
+			if s.Error == io.EOF {
+				return got, nil
+			}
+			return got, s.Error
+		}
+		i := s.Value.Iter()
+		for i.Advance() == TypeRoot {
+			_, elem, err := i.Root(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := elem.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, string(b))
+		}
+	}
+	t.Fatal("stream closed without error")
+	return nil, nil
+}
+
+func TestParseArrayStream(t *testing.T) {
+	var want []string
+	var array bytes.Buffer
+	array.WriteString(" [\n")
+	for n, tt := range testCases {
+		msg := loadCompressed(t, tt.name)
+		pj, err := Parse(msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		i := pj.Iter()
+		b, err := i.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, string(b))
+		if n > 0 {
+			array.WriteString(",\n")
+		}
+		array.Write(msg)
+	}
+	array.WriteString("\n]\n")
+
+	for _, size := range []int{streamChunkSize, 1 << 20, 100} {
+		got, err := arrayStreamElements(t, iotest.HalfReader(bytes.NewReader(array.Bytes())), size)
+		if err != nil {
+			t.Fatal(size, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("size %d: got %d elements, want %d", size, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("size %d: element %d mismatch", size, i)
+			}
+		}
+	}
+
+	testCases := []struct {
+		js   string
+		want []string
+	}{
+		{js: "[]", want: nil},
+		{js: " [\n ] \n", want: nil},
+		{js: "[1]", want: []string{"1"}},
+		{js: `[1,"a,b",{"x":[1,2]} , [3,4],null ,true]`, want: []string{"1", `"a,b"`, `{"x":[1,2]}`, "[3,4]", "null", "true"}},
+		{js: "[\"\\\"],\\\\\",\n{\n\"a\":\n1\n}\n]", want: []string{`"\"],\\"`, `{"a":1}`}},
+	}
+	for _, tc := range testCases {
+		for _, size := range []int{streamChunkSize, 1} {
+			// Read a byte at a time to split the input everywhere.
+			got, err := arrayStreamElements(t, iotest.OneByteReader(strings.NewReader(tc.js)), size)
+			if err != nil {
+				t.Fatalf("%q: %v", tc.js, err)
+			}
+			if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+				t.Errorf("%q: got %q, want %q", tc.js, got, tc.want)
+			}
+		}
+	}
+
+	// Public API
+	res := make(chan Stream, 10)
+	ParseArrayStream(strings.NewReader(`[{"a":1},[[2]]]`), res, nil, WithMaxDepth(1))
+	if s := <-res; s.Error == nil || s.Error == io.EOF {
+		t.Fatalf("want depth error, got %v", s.Error)
+	}
+}
+
+func TestParseArrayStreamError(t *testing.T) {
+	testCases := []struct {
+		js   string
+		want ParseError
+	}{
+		{js: `{"a":1}`, want: ParseError{Offset: 0, Line: 1, Column: 1, Stage: 1, Kind: ErrorKindUnexpectedCharacter}},
+		{js: "", want: ParseError{Offset: 0, Line: 1, Column: 1, Stage: 1, Kind: ErrorKindUnexpectedEnd}},
+		{js: "[1,\n2", want: ParseError{Offset: 5, Line: 2, Column: 2, Stage: 1, Kind: ErrorKindUnexpectedEnd}},
+		{js: "[1,\n]", want: ParseError{Offset: 4, Line: 2, Column: 1, Stage: 1, Kind: ErrorKindUnexpectedCharacter}},
+		{js: "[,1]", want: ParseError{Offset: 1, Line: 1, Column: 2, Stage: 1, Kind: ErrorKindUnexpectedCharacter}},
+		{js: "[1,,2]", want: ParseError{Offset: 3, Line: 1, Column: 4, Stage: 1, Kind: ErrorKindUnexpectedCharacter}},
+		{js: "[1}", want: ParseError{Offset: 2, Line: 1, Column: 3, Stage: 1, Kind: ErrorKindUnexpectedCharacter}},
+		{js: "[1]\n [2]", want: ParseError{Offset: 5, Line: 2, Column: 2, Stage: 1, Kind: ErrorKindTrailingData}},
+	}
+	for _, tc := range testCases {
+		_, err := arrayStreamElements(t, strings.NewReader(tc.js), streamChunkSize)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%q: want *ParseError, got %T: %v", tc.js, err, err)
+		}
+		if *perr != tc.want {
+			t.Errorf("%q: got %+v, want %+v", tc.js, *perr, tc.want)
+		}
+	}
+
+	// Errors in elements are found by the parser,
+	// and located in the input.
+	elemCases := []struct {
+		js   string
+		size int
+		want ParseError
+	}{
+		{js: `[1,{"a":tru}]`, size: streamChunkSize, want: ParseError{Offset: 8, Line: 1, Column: 9, Stage: 2, Kind: ErrorKindInvalidLiteral}},
+		{js: "[1,\n 2 3]", size: streamChunkSize, want: ParseError{Offset: 7, Line: 2, Column: 4, Stage: 2, Kind: ErrorKindTrailingData}},
+		// The error is in the third block.
+		{js: "[\n1,\n2,\n  3,\n4 x]", size: 4, want: ParseError{Offset: 15, Line: 5, Column: 3, Stage: 1, Kind: ErrorKindTrailingData}},
+		{js: "[1, 2,\n 3, [4\n}]", size: 4, want: ParseError{Offset: 14, Line: 3, Column: 1, Stage: 2, Kind: ErrorKindUnexpectedCharacter}},
+	}
+	for _, tc := range elemCases {
+		_, err := arrayStreamElements(t, strings.NewReader(tc.js), tc.size)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%q: want *ParseError, got %T: %v", tc.js, err, err)
+		}
+		if *perr != tc.want {
+			t.Errorf("%q: got %+v, want %+v", tc.js, *perr, tc.want)
+		}
+	}
+
+	// Read errors are returned.
+	r := iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("[1,2,3]")))
+	if _, err := arrayStreamElements(t, r, streamChunkSize); err != iotest.ErrTimeout {
+		t.Fatalf("got %v, want %v", err, iotest.ErrTimeout)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ErrorKind describes the kind of error found when parsing.
+type ErrorKind uint8
+
+const (
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindUnclosedString
+	ErrorKindInvalidString
+	ErrorKindControlCharacter
+	ErrorKindInvalidLiteral
+	ErrorKindInvalidNumber
+	ErrorKindUnexpectedCharacter
+	ErrorKindUnexpectedEnd
+	ErrorKindTrailingData
+	ErrorKindDepthExceeded
+	ErrorKindInvalidUTF8
+	ErrorKindUnclosedComment
+	ErrorKindDuplicateKey
+)
+
+// String returns the error kind as a string.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindUnknown:
+		return "invalid json"
+	case ErrorKindUnclosedString:
+		return "unclosed string"
+	case ErrorKindInvalidString:
+		return "invalid escape sequence in string"
+	case ErrorKindControlCharacter:
+		return "control character in string"
+	case ErrorKindInvalidLiteral:
+		return "invalid literal"
+	case ErrorKindInvalidNumber:
+		return "invalid number"
+	case ErrorKindUnexpectedCharacter:
+		return "unexpected character"
+	case ErrorKindUnexpectedEnd:
+		return "unexpected end of input"
+	case ErrorKindTrailingData:
+		return "trailing data"
+	case ErrorKindDepthExceeded:
+		return "maximum depth exceeded"
+	case ErrorKindInvalidUTF8:
+		return "invalid UTF-8"
+	case ErrorKindUnclosedComment:
+		return "unclosed comment"
+	case ErrorKindDuplicateKey:
+		return "duplicate key"
+	}
+	return "(invalid)"
+}
+
+// ParseError is returned when the input is not valid JSON.
+type ParseError struct {
+	// Offset is the byte offset in the input where the error was found.
+	Offset uint64
+
+	// Line and Column where the error was found. Both are 1-based.
+	// The column is counted in bytes.
+	Line, Column int
+
+	// Stage is the parsing stage that found the error, 1 or 2.
+	Stage int
+
+	// Kind is the kind of error.
+	Kind ErrorKind
+
+	// Key is the duplicated key for ErrorKindDuplicateKey.
+	Key string
+}
+
+// Error returns a description of the error and its location.
+func (e *ParseError) Error() string {
+	if e.Kind == ErrorKindDuplicateKey {
+		return fmt.Sprintf("%s %q at offset %d, line %d, column %d (stage %d)", e.Kind, e.Key, e.Offset, e.Line, e.Column, e.Stage)
+	}
+	return fmt.Sprintf("%s at offset %d, line %d, column %d (stage %d)", e.Kind, e.Offset, e.Line, e.Column, e.Stage)
+}
+
+// newParseError returns an error at the specified offset of the message.
+func newParseError(msg []byte, offset uint64, stage int, kind ErrorKind) *ParseError {
+	if offset > uint64(len(msg)) {
+		offset = uint64(len(msg))
+	}
+	before := msg[:offset]
+	return &ParseError{
+		Offset: offset,
+		Line:   bytes.Count(before, []byte{'\n'}) + 1,
+		Column: len(before) - bytes.LastIndexByte(before, '\n'),
+		Stage:  stage,
+		Kind:   kind,
+	}
+}
+
+// locateError returns err at its location in input, if it is a *ParseError
+// found in a message that starts at offset start in input.
+func locateError(err error, input []byte, start uint64) error {
+	perr, ok := err.(*ParseError)
+	if !ok || perr == nil {
+		return err
+	}
+	return perr.locate(input, start)
+}
+
+// locate returns a copy of e, found in a message that starts at offset start in input,
+// with the location in input.
+func (e *ParseError) locate(input []byte, start uint64) *ParseError {
+	located := newParseError(input, start+e.Offset, e.Stage, e.Kind)
+	located.Key = e.Key
+	return located
+}
+
+// stage1Error returns the error for a message that has failed stage 1.
+// Stage 1 only keeps track of whether an error occurred,
+// so the message is scanned again to locate it.
+func (pj *internalParsedJson) stage1Error() *ParseError {
+	if pj.errStage1 != nil {
+		return pj.errStage1
+	}
+	buf := pj.Message
+	depth := 0
+	inString := false
+	stringStart := 0
+	started := false
+	recordDone := false
+	newline := false
+	afterString := false // a string in an object or array has just ended
+
+	for i := 0; i < len(buf); i++ {
+		c := buf[i]
+		if inString {
+			switch {
+			case c == '\\':
+				i++
+			case c == '"':
+				inString = false
+				afterString = depth > 0
+				if depth == 0 {
+					// String at the root.
+					recordDone = true
+					newline = false
+				}
+			case c < 0x20:
+				return newParseError(buf, uint64(i), 1, ErrorKindControlCharacter)
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\r':
+			continue
+		case '\n':
+			newline = true
+			continue
+		case '\\':
+			// Backslashes are only valid in strings.
+			return newParseError(buf, uint64(i), 1, ErrorKindUnexpectedCharacter)
+		}
+		if afterString {
+			afterString = false
+			if c != ':' && c != ',' && c != '}' && c != ']' {
+				return newParseError(buf, uint64(i), 1, ErrorKindUnexpectedCharacter)
+			}
+		}
+		if depth == 0 {
+			if recordDone && !(pj.ndjson != 0 && newline || pj.separator >= separatorWhitespace) {
+				return newParseError(buf, uint64(i), 1, ErrorKindTrailingData)
+			}
+			if c != '{' && c != '[' && !pj.scalarStart(c) {
+				return newParseError(buf, uint64(i), 1, ErrorKindUnexpectedCharacter)
+			}
+			started = true
+			recordDone = false
+			if c != '"' && pj.scalarStart(c) {
+				// Number or atom at the root, skip to the end of it.
+				for i+1 < len(buf) && isNotStructuralOrWhitespace(buf[i+1]) != 0 {
+					i++
+				}
+				recordDone = true
+				newline = false
+				continue
+			}
+		}
+		switch c {
+		case '"':
+			inString = true
+			stringStart = i
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				recordDone = true
+				newline = false
+			}
+		}
+	}
+	switch {
+	case inString:
+		return newParseError(buf, uint64(stringStart), 1, ErrorKindUnclosedString)
+	case depth > 0 || !started:
+		return newParseError(buf, uint64(len(buf)), 1, ErrorKindUnexpectedEnd)
+	}
+	// Stage 1 rejects a message that doesn't end with a value,
+	// so report the start of the last token.
+	end := len(bytes.TrimRight(buf, " \t\n\r"))
+	start := end
+	for start > 0 && isNotStructuralOrWhitespace(buf[start-1]) != 0 {
+		start--
+	}
+	if start == end && start > 0 {
+		start--
+	}
+	return newParseError(buf, uint64(start), 1, ErrorKindUnexpectedCharacter)
+}
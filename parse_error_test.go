@@ -0,0 +1,135 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		name   string
+		js     string
+		ndjson bool
+		kind   ErrorKind
+		stage  int
+		offset uint64
+		line   int
+		column int
+	}{
+		{name: "literal", js: `{"a":tru}`, kind: ErrorKindInvalidLiteral, stage: 2, offset: 5, line: 1, column: 6},
+		{name: "number", js: `[1, 2.x]`, kind: ErrorKindInvalidNumber, stage: 2, offset: 4, line: 1, column: 5},
+		{name: "escape", js: "{\"a\":\n  \"\\q\"}", kind: ErrorKindInvalidString, stage: 2, offset: 8, line: 2, column: 3},
+		{name: "control", js: "{\"a\":\"x\x01\"}", kind: ErrorKindControlCharacter, stage: 1, offset: 7, line: 1, column: 8},
+		{name: "unclosed-string", js: `{"a":"abc`, kind: ErrorKindUnclosedString, stage: 1, offset: 5, line: 1, column: 6},
+		{name: "unexpected-end-stage1", js: `[1,2,`, kind: ErrorKindUnexpectedEnd, stage: 1, offset: 5, line: 1, column: 6},
+		{name: "unexpected-end-array", js: `[1,2`, kind: ErrorKindUnexpectedEnd, stage: 2, offset: 4, line: 1, column: 5},
+		{name: "unexpected-end-stage2", js: `[[1,2]`, kind: ErrorKindUnexpectedEnd, stage: 2, offset: 6, line: 1, column: 7},
+		{name: "empty", js: ``, kind: ErrorKindUnexpectedEnd, stage: 1, offset: 0, line: 1, column: 1},
+		{name: "unexpected", js: `{"a" 1}`, kind: ErrorKindUnexpectedCharacter, stage: 2, offset: 5, line: 1, column: 6},
+		{name: "root", js: `xyz`, kind: ErrorKindUnexpectedCharacter, stage: 1, offset: 0, line: 1, column: 1},
+		{name: "root-literal", js: `nul`, kind: ErrorKindInvalidLiteral, stage: 2, offset: 0, line: 1, column: 1},
+		{name: "root-trailing-stage1", js: `1 x`, kind: ErrorKindTrailingData, stage: 1, offset: 2, line: 1, column: 3},
+		{name: "root-trailing-stage2", js: `"a" 1`, kind: ErrorKindTrailingData, stage: 2, offset: 4, line: 1, column: 5},
+		{name: "trailing-stage1", js: `{"a":1} x`, kind: ErrorKindTrailingData, stage: 1, offset: 8, line: 1, column: 9},
+		{name: "trailing-stage2", js: `[1,2]]`, kind: ErrorKindTrailingData, stage: 2, offset: 5, line: 1, column: 6},
+		{name: "ndjson-trailing", js: "{\"a\":1}\n{\"b\":2} {}", ndjson: true, kind: ErrorKindTrailingData, stage: 2, offset: 16, line: 2, column: 9},
+		{name: "ndjson-literal", js: "{\"a\":1}\n{\"b\":nul}", ndjson: true, kind: ErrorKindInvalidLiteral, stage: 2, offset: 13, line: 2, column: 6},
+		{name: "leading-space", js: " fase", kind: ErrorKindInvalidLiteral, stage: 2, offset: 1, line: 1, column: 2},
+		{name: "leading-lines", js: "\n\n   fase", kind: ErrorKindInvalidLiteral, stage: 2, offset: 5, line: 3, column: 4},
+		{name: "leading-stage1", js: "\n  {\"a\":\"x\x01\"}", kind: ErrorKindControlCharacter, stage: 1, offset: 10, line: 2, column: 10},
+		{name: "leading-trailing", js: "\n{\"a\":1}\n{bad}\n", kind: ErrorKindTrailingData, stage: 2, offset: 9, line: 3, column: 1},
+		{name: "ndjson-leading", js: "\n{\"a\":1}\n{bad}\n", ndjson: true, kind: ErrorKindUnexpectedCharacter, stage: 2, offset: 10, line: 3, column: 2},
+		{name: "backslash", js: "{\\t\"a\":1}", kind: ErrorKindUnexpectedCharacter, stage: 2, offset: 1, line: 1, column: 2},
+		{name: "after-string", js: "{\t\"\\\\a.\\\\\"x\\\"f\\\"j\":\"f\"}", kind: ErrorKindUnexpectedCharacter, stage: 1, offset: 10, line: 1, column: 11},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			if tc.ndjson {
+				_, err = ParseND([]byte(tc.js), nil)
+			} else {
+				_, err = Parse([]byte(tc.js), nil)
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("want *ParseError, got %T: %v", err, err)
+			}
+			want := ParseError{Offset: tc.offset, Line: tc.line, Column: tc.column, Stage: tc.stage, Kind: tc.kind}
+			if *perr != want {
+				t.Errorf("got %+v (%v), want %+v", *perr, perr, want)
+			}
+			if tc.ndjson {
+				return
+			}
+			// The other parsers report the same location.
+			_, err = ParseReader(strings.NewReader(tc.js), nil)
+			if !errors.As(err, &perr) {
+				t.Fatalf("ParseReader: want *ParseError, got %T: %v", err, err)
+			}
+			if perr.Offset != want.Offset || perr.Line != want.Line || perr.Column != want.Column {
+				t.Errorf("ParseReader: got %v, want %+v", perr, want)
+			}
+		})
+	}
+}
+
+func TestParseErrorUnclosedString(t *testing.T) {
+	testCases := []struct {
+		name   string
+		js     string
+		offset uint64
+	}{
+		{name: "quote", js: `"`, offset: 0},
+		{name: "array", js: `["`, offset: 1},
+		{name: "root", js: `"abc`, offset: 0},
+		{name: "large", js: `{"a":"` + strings.Repeat("a", 4<<20), offset: 5},
+	}
+
+	parsers := []struct {
+		name  string
+		parse func(b []byte) error
+	}{
+		{name: "Parse", parse: func(b []byte) error { _, err := Parse(b, nil); return err }},
+		{name: "ParseND", parse: func(b []byte) error { _, err := ParseND(b, nil); return err }},
+		{name: "ParseReader", parse: func(b []byte) error {
+			_, err := ParseReader(strings.NewReader(string(b)), nil)
+			return err
+		}},
+	}
+
+	for _, tc := range testCases {
+		for _, p := range parsers {
+			t.Run(tc.name+"/"+p.name, func(t *testing.T) {
+				// Stage 2 runs concurrently, so repeat to catch it reading beyond the string.
+				for i := 0; i < 10; i++ {
+					err := p.parse([]byte(tc.js))
+					var perr *ParseError
+					if !errors.As(err, &perr) {
+						t.Fatalf("want *ParseError, got %T: %v", err, err)
+					}
+					if perr.Kind != ErrorKindUnclosedString || perr.Offset != tc.offset {
+						t.Fatalf("got %v, want unclosed string at offset %d", perr, tc.offset)
+					}
+				}
+			})
+		}
+	}
+}
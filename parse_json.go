@@ -0,0 +1,198 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"sync"
+	"unicode"
+)
+
+func (pj *internalParsedJson) initialize(size int) {
+	// Estimate the tape size to be about 15% of the length of the JSON message
+	avgTapeSize := size * 15 / 100
+	if cap(pj.Tape) < avgTapeSize {
+		pj.Tape = make([]uint64, 0, avgTapeSize)
+	}
+	pj.Tape = pj.Tape[:0]
+
+	stringsSize := size / 10
+	if stringsSize < 128 {
+		stringsSize = 128 // always allocate at least 128 for the string buffer
+	}
+	if pj.Strings != nil && cap(pj.Strings.B) >= stringsSize {
+		pj.Strings.B = pj.Strings.B[:0]
+	} else {
+		pj.Strings = &TStrings{make([]byte, 0, stringsSize)}
+	}
+	if cap(pj.containingScopeOffset) < maxdepth {
+		pj.containingScopeOffset = make([]uint64, 0, maxdepth)
+	}
+	pj.containingScopeOffset = pj.containingScopeOffset[:0]
+	pj.roots = pj.roots[:0]
+	pj.rootBase = RootInfo{Line: 1}
+	pj.rootTape = pj.rootTape[:0]
+	pj.tapeStart = 0
+	pj.indexesChan = indexChan{}
+	pj.simd = SupportedCPU()
+}
+
+// rootSeparator is what separates multiple root values in a message.
+type rootSeparator uint8
+
+const (
+	// separatorNone only allows a single root value.
+	separatorNone rootSeparator = iota
+	// separatorNewline separates root values by newlines (NDJSON).
+	separatorNewline
+	// separatorWhitespace separates root values by optional whitespace (concatenated JSON).
+	separatorWhitespace
+	// separatorRecord separates root values by whitespace or RS (RFC 7464).
+	separatorRecord
+)
+
+// recordSeparator is the RS character that starts each value of a JSON text sequence.
+const recordSeparator = 0x1e
+
+// blankRecordSeparators returns msg with record separators replaced by whitespace,
+// when parsing a JSON text sequence.
+// The input is copied if it contains record separators.
+// '\r' is used, since it is still invalid within strings and doesn't change line numbers.
+func (pj *internalParsedJson) blankRecordSeparators(msg []byte) []byte {
+	if pj.separator != separatorRecord || bytes.IndexByte(msg, recordSeparator) < 0 {
+		return msg
+	}
+	msg = append([]byte(nil), msg...)
+	for i, c := range msg {
+		if c == recordSeparator {
+			msg[i] = '\r'
+		}
+	}
+	return msg
+}
+
+func (pj *internalParsedJson) parseMessage(msg []byte, ndjson bool) error {
+	if ndjson {
+		return pj.parseSeparated(msg, separatorNewline)
+	}
+	return pj.parseSeparated(msg, separatorNone)
+}
+
+// parseSeparated parses a message with root values separated by sep.
+func (pj *internalParsedJson) parseSeparated(msg []byte, sep rootSeparator) error {
+	// Cache message so we can point directly to strings
+	// TODO: Find out why TestVerifyTape/instruments fails without bytes.TrimSpace
+	pj.separator = sep
+	if sep == separatorNewline {
+		pj.ndjson = 1
+	} else {
+		pj.ndjson = 0
+	}
+	b := msg
+	input := pj.trimBOM(b)
+	bom := uint64(len(b) - len(input))
+	msg, err := pj.blankComments(input)
+	if err != nil {
+		return locateError(err, b, bom)
+	}
+	if pj.skipInvalid && sep == separatorNewline {
+		err = pj.parseRecords(input, msg, bom)
+	} else {
+		err = pj.parseStages(msg)
+		// Message starts after the leading whitespace.
+		err = locateError(err, b, bom+pj.rootBase.Offset)
+	}
+	if err == nil && sep == separatorNewline && len(msg) > 0 && &msg[0] != &input[0] {
+		// Newlines in blanked comments were not counted.
+		pj.countRootLines(input)
+	}
+	pj.rootBase.Offset += bom
+	return err
+}
+
+// parseStages runs both stages on msg, which must have comments blanked.
+func (pj *internalParsedJson) parseStages(msg []byte) error {
+	pj.Message = pj.blankRecordSeparators(bytes.TrimSpace(msg))
+	pj.initialize(len(pj.Message))
+	if leading := len(msg) - len(bytes.TrimLeftFunc(msg, unicode.IsSpace)); leading > 0 {
+		pj.rootBase.Offset = uint64(leading)
+		pj.rootBase.Line += bytes.Count(msg[:leading], []byte{'\n'})
+	}
+
+	// Make the capacity of the channel smaller than the number of slots.
+	// This way the sender will automatically block until the consumer
+	// has finished the slot it is working on.
+	if pj.indexChans == nil {
+		pj.indexChans = make(chan indexChan, indexSlots-2)
+	}
+	pj.buffersOffset = ^uint64(0)
+	pj.errStage2 = nil
+
+	// Do long inputs async
+	if len(pj.Message) > 8<<10 {
+		var errStage1, errStage2 error
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, done := pj.unifiedMachine(); !ok {
+				errStage2 = pj.errStage2
+				// Keep consuming...
+				if !done {
+					for idx := range pj.indexChans {
+						if idx.index == -1 {
+							break
+						}
+					}
+				}
+			}
+		}()
+		if !pj.findStructuralIndices() {
+			errStage1 = pj.stage1Error()
+		}
+		wg.Wait()
+		if errStage1 != nil {
+			return errStage1
+		}
+		return errStage2
+	}
+
+	if !pj.findStructuralIndices() {
+		// drain the channel until empty
+		for idx := range pj.indexChans {
+			if idx.index == -1 {
+				break
+			}
+		}
+		return pj.stage1Error()
+	}
+	if ok, _ := pj.unifiedMachine(); !ok {
+		// drain the channel until empty
+		for {
+			select {
+			case idx := <-pj.indexChans:
+				if idx.index == -1 {
+					return pj.errStage2
+				}
+				// Already drained.
+			default:
+				return pj.errStage2
+			}
+		}
+	}
+	return nil
+}
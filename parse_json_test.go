@@ -1,6 +1,3 @@
-//go:build !noasm && !appengine && gc
-// +build !noasm,!appengine,gc
-
 /*
  * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
  *
@@ -32,9 +29,6 @@ import (
 )
 
 func TestDemoNdjson(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	pj := internalParsedJson{}
 
 	if err := pj.parseMessage([]byte(demo_ndjson), true); err != nil {
@@ -45,10 +39,6 @@ func TestDemoNdjson(t *testing.T) {
 }
 
 func TestNdjsonEmptyLines(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
-
 	ndjson_emptylines := []string{`{"zero":"emptylines"}
 {"c":"d"}`,
 		`{"single":"emptyline"}
@@ -74,10 +64,6 @@ func TestNdjsonEmptyLines(t *testing.T) {
 }
 
 func BenchmarkNdjsonStage2(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
-
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 	pj := internalParsedJson{}
 
@@ -93,9 +79,6 @@ func BenchmarkNdjsonStage2(b *testing.B) {
 }
 
 func BenchmarkNdjsonStage1(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 
 	pj := internalParsedJson{}
@@ -113,9 +96,6 @@ func BenchmarkNdjsonStage1(b *testing.B) {
 }
 
 func BenchmarkNdjsonColdCountStar(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 
 	b.SetBytes(int64(len(ndjson)))
@@ -132,9 +112,6 @@ func BenchmarkNdjsonColdCountStar(b *testing.B) {
 }
 
 func BenchmarkNdjsonColdCountStarWithWhere(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 	const want = 110349
 	runtime.GC()
@@ -502,7 +479,6 @@ var atoftests = []atofTest{
 }
 
 func TestParseFloat64(t *testing.T) {
-
 	for i := 0; i < len(atoftests); i++ {
 		test := &atoftests[i]
 		t.Run(test.in, func(t *testing.T) {
@@ -538,16 +514,25 @@ func TestParseFloat64(t *testing.T) {
 }
 
 func TestParseString(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
+	t.Run("generic", func(t *testing.T) {
+		testParseString(t, parseStringGeneric)
+	})
+	if SupportedCPU() {
+		pj := internalParsedJson{simd: true}
+		t.Run("simd", func(t *testing.T) {
+			testParseString(t, pj.parseStringUnescape)
+		})
 	}
+}
+
+func testParseString(t *testing.T, f func([]byte, *[]byte) bool) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// enclose test string in quotes (as validated by stage 1)
 			buf := []byte(fmt.Sprintf(`"%s"`, tt.str))
 			dest := make([]byte, 0, len(buf)+32 /* safety margin as parseString writes full AVX2 words */)
 
-			success := parseStringSimd(buf, &dest)
+			success := f(buf, &dest)
 
 			if success != tt.success {
 				t.Errorf("TestParseString() got = %v, want %v", success, tt.success)
@@ -566,10 +551,18 @@ func TestParseString(t *testing.T) {
 }
 
 func TestParseStringValidateOnly(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
+	t.Run("generic", func(t *testing.T) {
+		testParseStringValidateOnly(t, parseStringValidateOnlyGeneric)
+	})
+	if SupportedCPU() {
+		pj := internalParsedJson{simd: true}
+		t.Run("simd", func(t *testing.T) {
+			testParseStringValidateOnly(t, pj.parseStringValidateOnly)
+		})
 	}
+}
 
+func testParseStringValidateOnly(t *testing.T, f func([]byte, *uint64, *uint64, *bool) bool) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// enclose test string in quotes (as validated by stage 1)
@@ -578,7 +571,7 @@ func TestParseStringValidateOnly(t *testing.T) {
 			dst_length := uint64(0)
 			need_copy := false
 			l := uint64(len(buf))
-			success := parseStringSimdValidateOnly(buf, &l, &dst_length, &need_copy)
+			success := f(buf, &l, &dst_length, &need_copy)
 
 			if success != tt.success {
 				t.Errorf("TestParseString() got = %v, want %v", success, tt.success)
@@ -593,10 +586,6 @@ func TestParseStringValidateOnly(t *testing.T) {
 }
 
 func TestParseStringValidateOnlyBeyondBuffer(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
-
 	t.Skip()
 
 	buf := []byte(fmt.Sprintf(`"%s`, "   "))
@@ -604,7 +593,8 @@ func TestParseStringValidateOnlyBeyondBuffer(t *testing.T) {
 	dst_length := uint64(0)
 	need_copy := false
 	l := uint64(len(buf)) + 32
-	success := parseStringSimdValidateOnly(buf, &l, &dst_length, &need_copy)
+	pj := internalParsedJson{simd: SupportedCPU()}
+	success := pj.parseStringValidateOnly(buf, &l, &dst_length, &need_copy)
 	if !success {
 		t.Errorf("TestParseStringValidateOnlyBeyondBuffer() got = %v, want %v", success, false)
 	}
@@ -680,10 +670,6 @@ func BenchmarkParseNumberRandomFloats(b *testing.B) {
 }
 
 func TestVerifyTape(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
-
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			ref := loadCompressed(t, tt.name)
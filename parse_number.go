@@ -18,6 +18,7 @@ package simdjson
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"reflect"
 	"strconv"
@@ -147,3 +148,220 @@ func unsafeBytesToString(b []byte) (s string) {
 	stringHeader.Len = length
 	return s
 }
+
+// numberLength returns the length of the number at the start of buf.
+// 0 is returned if the number is followed by an invalid character.
+func numberLength(buf []byte) int {
+	for i, v := range buf {
+		t := isNumberRune[v]
+		if t == 0 {
+			return 0
+		}
+		if t == isEOVFlag {
+			return i
+		}
+	}
+	return len(buf)
+}
+
+// isValidNumber returns whether b is a number as defined by the JSON grammar.
+func isValidNumber(b []byte) bool {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	digits := func() int {
+		start := i
+		for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	switch n := digits(); {
+	case n == 0:
+		return false
+	case n > 1 && b[i-n] == '0':
+		// Leading zero.
+		return false
+	}
+	if i < len(b) && b[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(b)
+}
+
+// isIntegerText returns whether the valid number b is in integer notation.
+func isIntegerText(b []byte) bool {
+	for _, c := range b {
+		if isNumberRune[c]&isFloatOnlyFlag != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bigNumberLength returns the length of the number at the start of buf,
+// if it cannot be represented by the tape entry returned by parseNumber.
+// That is integers that overflow 64 bits, numbers outside the float64 range
+// and floats that do not convert back to the same decimal value.
+// 0 is returned if the entry is exact or the number is invalid.
+func bigNumberLength(buf []byte, id, val uint64) int {
+	if id != 0 && Tag(id>>JSONTAGOFFSET) != TagFloat {
+		return 0
+	}
+	n := numberLength(buf)
+	if n == 0 || !isValidNumber(buf[:n]) {
+		return 0
+	}
+	if id == 0 {
+		// A valid number that could not be parsed is out of range.
+		return n
+	}
+	if FloatFlags(id&JSONVALUEMASK).Contains(FloatOverflowedInteger) || !floatRoundTrips(buf[:n], math.Float64frombits(val)) {
+		return n
+	}
+	return 0
+}
+
+// maxRoundTripDigits is the maximum number of significant digits
+// of a decimal that converts to and from a float64 unchanged.
+const maxRoundTripDigits = 17
+
+// floatRoundTrips returns whether the shortest decimal representation of f
+// has the same value as the valid number text.
+func floatRoundTrips(text []byte, f float64) bool {
+	var a, b [maxRoundTripDigits]byte
+	digits, exp, neg, ok := decimalDigits(a[:0], text)
+	if !ok {
+		return false
+	}
+	var tmp [32]byte
+	fDigits, fExp, fNeg, _ := decimalDigits(b[:0], strconv.AppendFloat(tmp[:0], f, 'e', -1, 64))
+	return exp == fExp && neg == fNeg && string(digits) == string(fDigits)
+}
+
+// decimalDigits returns the significant digits of the valid number text,
+// without leading and trailing zeros, and the exponent so that
+// the absolute value is 0.digits * 10^exp.
+// Zero has no digits and exponent 0.
+// Digits are appended to dst, and ok is false if they don't fit within its capacity
+// or the exponent is out of range.
+func decimalDigits(dst, text []byte) (digits []byte, exp int, neg, ok bool) {
+	i := 0
+	if text[0] == '-' {
+		neg = true
+		i++
+	}
+	zeros := 0 // zeros that are only significant if followed by another digit
+	point := false
+	for ; i < len(text); i++ {
+		c := text[i]
+		if c == '.' {
+			point = true
+			continue
+		}
+		if c == 'e' || c == 'E' {
+			e, err := strconv.Atoi(string(text[i+1:]))
+			if err != nil || e > 1e6 || e < -1e6 {
+				return nil, 0, neg, false
+			}
+			exp += e
+			break
+		}
+		switch {
+		case c != '0':
+			if len(dst)+zeros+1 > cap(dst) {
+				return nil, 0, neg, false
+			}
+			for ; zeros > 0; zeros-- {
+				dst = append(dst, '0')
+			}
+			dst = append(dst, c)
+		case len(dst) > 0:
+			zeros++
+		case point:
+			// Leading zero after the decimal point.
+			exp--
+			continue
+		default:
+			continue
+		}
+		if !point {
+			exp++
+		}
+	}
+	if len(dst) == 0 {
+		exp = 0
+	}
+	return dst, exp, neg, true
+}
+
+// parseNumberTextFloat returns the value of the valid number text as a float.
+func parseNumberTextFloat(text []byte) (float64, error) {
+	v, err := strconv.ParseFloat(unsafeBytesToString(text), 64)
+	if err != nil {
+		return 0, fmt.Errorf("number %s overflows float64", text)
+	}
+	return v, nil
+}
+
+// parseNumberTextInt returns the value of the valid number text as an integer.
+// Floats within range are converted.
+func parseNumberTextInt(text []byte) (int64, error) {
+	if isIntegerText(text) {
+		v, err := strconv.ParseInt(unsafeBytesToString(text), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("number %s overflows int64", text)
+		}
+		return v, nil
+	}
+	v, err := parseNumberTextFloat(text)
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxInt64 {
+		return 0, errors.New("float value overflows int64")
+	}
+	if v < math.MinInt64 {
+		return 0, errors.New("float value underflows int64")
+	}
+	return int64(v), nil
+}
+
+// parseNumberTextUint returns the value of the valid number text as an unsigned integer.
+// Positive floats within range are converted.
+func parseNumberTextUint(text []byte) (uint64, error) {
+	if isIntegerText(text) {
+		if text[0] == '-' {
+			return 0, errors.New("integer value is negative. cannot convert to uint")
+		}
+		v, err := strconv.ParseUint(unsafeBytesToString(text), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("number %s overflows uint64", text)
+		}
+		return v, nil
+	}
+	v, err := parseNumberTextFloat(text)
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxUint64 {
+		return 0, errors.New("float value overflows uint64")
+	}
+	if v < 0 {
+		return 0, errors.New("float value is negative. cannot convert to uint")
+	}
+	return uint64(v), nil
+}
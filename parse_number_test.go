@@ -30,7 +30,7 @@ import (
 func TestNumberIsValid(t *testing.T) {
 	// From: https://stackoverflow.com/a/13340826
 	var jsonNumberRegexp = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?$`)
-	isValidNumber := func(s string) bool {
+	parsesNumber := func(s string) bool {
 		tag, _ := parseNumber([]byte(s))
 		return tag != 0
 	}
@@ -84,9 +84,12 @@ func TestNumberIsValid(t *testing.T) {
 	}
 
 	for _, test := range validTests {
-		if !isValidNumber(test) {
+		if !parsesNumber(test) {
 			t.Errorf("%s should be valid", test)
 		}
+		if !isValidNumber([]byte(test)) {
+			t.Errorf("%s should be valid by isValidNumber", test)
+		}
 
 		if !jsonNumberRegexp.MatchString(test) {
 			t.Errorf("%s should be valid but regexp does not match", test)
@@ -118,9 +121,12 @@ func TestNumberIsValid(t *testing.T) {
 	}
 
 	for _, test := range invalidTests {
-		if isValidNumber(test) {
+		if parsesNumber(test) {
 			t.Errorf("%s should be invalid", test)
 		}
+		if isValidNumber([]byte(test)) {
+			t.Errorf("%s should be invalid by isValidNumber", test)
+		}
 
 		if jsonNumberRegexp.MatchString(test) {
 			t.Errorf("%s should be invalid but matches regexp", test)
@@ -128,6 +134,56 @@ func TestNumberIsValid(t *testing.T) {
 	}
 }
 
+func TestFloatRoundTrips(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"0", true},
+		{"-0.0e10", true},
+		{"0.1", true},
+		{"-12.5e-3", true},
+		{"100", true},
+		{"1.0000000000000000000000", true},
+		{"0.30000000000000004", true},
+		{"1.7976931348623157e308", true},
+		{"5e-324", true},
+		{"100000000000000000000000000000.0", true},
+		{"0.1000000000000000000001", false},
+		{"3.141592653589793238462643383279", false},
+		{"9007199254740993", false},
+		{"1e-400", false},
+		{"2.5e-324", false},
+	}
+	for _, tt := range tests {
+		f, err := strconv.ParseFloat(tt.text, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := floatRoundTrips([]byte(tt.text), f); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.text, got, tt.want)
+		}
+	}
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 10000; i++ {
+		f := math.Float64frombits(rng.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		for _, text := range []string{strconv.FormatFloat(f, 'g', -1, 64), strconv.FormatFloat(f, 'f', -1, 64)} {
+			if !floatRoundTrips([]byte(text), f) {
+				t.Fatalf("%s should round trip", text)
+			}
+		}
+		// Append a digit to the mantissa.
+		text := strconv.FormatFloat(f, 'e', 16, 64)
+		text = text[:18] + "1" + text[18:]
+		if floatRoundTrips([]byte(text), f) {
+			t.Fatalf("%s should not round trip", text)
+		}
+	}
+}
+
 func closeEnough(d1, d2 float64) (ce bool) {
 	return math.Abs((d1-d2)/(0.5*(d1+d2))) < 1e-20
 }
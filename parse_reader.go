@@ -0,0 +1,312 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// readerWindowSize is the number of bytes ParseReader reads at once.
+const readerWindowSize = 1 << 20
+
+// ParseReader will parse a single JSON document read from r.
+// The input is read in windows of a fixed size and each window is passed to stage 1
+// as it is read, so the full document is never held in memory.
+// The result is the same as parsing the full input with Parse,
+// except that strings are always copied and Message will be nil.
+// An optional block of previously parsed json can be supplied to reduce allocations.
+// Invalid JSON is reported as a *ParseError with the offset in the input.
+// Errors from r are returned as is.
+func ParseReader(r io.Reader, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error) {
+	pj, err := newInternalParsedJson(reuse, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = pj.parseReader(r, readerWindowSize)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &pj.ParsedJson
+	parsed.internal = pj
+	return parsed, nil
+}
+
+// window is a part of the input of ParseReader.
+// Stage 2 switches to msg when it receives an index buffer with the window attached.
+type window struct {
+	msg []byte
+
+	// shift is the offset of msg in the previous window.
+	shift uint64
+
+	// offset, line and column of the start of msg in the input.
+	offset       uint64
+	line, column int
+}
+
+// advance moves the start of the window past b.
+func (w *window) advance(b []byte) {
+	w.offset += uint64(len(b))
+	if n := bytes.Count(b, []byte{'\n'}); n > 0 {
+		w.line += n
+		w.column = len(b) - bytes.LastIndexByte(b, '\n')
+	} else {
+		w.column += len(b)
+	}
+}
+
+// adjust changes the location of an error found in msg to the location in the input.
+func (w *window) adjust(err *ParseError) {
+	if err.Line == 1 {
+		err.Column += w.column - 1
+	}
+	err.Line += w.line - 1
+	err.Offset += w.offset
+}
+
+func (pj *internalParsedJson) parseReader(r io.Reader, size int) error {
+	pj.Message = nil
+	pj.initialize(size)
+	pj.ndjson = 0
+	pj.separator = separatorNone
+	pj.window = nil
+
+	// Windows are dropped when stage 2 is done with them,
+	// so strings cannot point into the input.
+	copyStrings := pj.copyStrings
+	pj.copyStrings = true
+	defer func() {
+		pj.copyStrings = copyStrings
+	}()
+
+	if pj.indexChans == nil {
+		pj.indexChans = make(chan indexChan, indexSlots-2)
+	}
+	pj.buffersOffset = ^uint64(0)
+	pj.errStage1 = nil
+	pj.errStage2 = nil
+
+	var errStage2 *ParseError
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if ok, done := pj.unifiedMachine(); !ok {
+			errStage2 = pj.errStage2
+			if pj.window != nil {
+				pj.window.adjust(errStage2)
+			}
+			// Keep consuming...
+			if !done {
+				for idx := range pj.indexChans {
+					if idx.index == -1 {
+						break
+					}
+				}
+			}
+		}
+	}()
+	err := pj.readStructuralIndices(r, size)
+	pj.indexChans <- indexChan{index: -1}
+	wg.Wait()
+	pj.Message = nil
+	pj.window = nil
+	// Roots are located in windows that are gone.
+	pj.roots = pj.roots[:0]
+
+	if err != nil {
+		// Stage 2 may have found an error before the one stopping stage 1.
+		// It cannot have reached the end, since stage 1 stopped early.
+		if errStage1, ok := err.(*ParseError); ok && errStage2 != nil &&
+			errStage2.Kind != ErrorKindUnexpectedEnd && errStage2.Offset < errStage1.Offset {
+			return errStage2
+		}
+		return err
+	}
+	if errStage2 != nil {
+		return errStage2
+	}
+	return nil
+}
+
+// readStructuralIndices reads the input in windows and sends the structural indices to stage 2.
+// Each window starts with the part of the previous window that has not been processed
+// and continues with at least size bytes read from r.
+func (pj *internalParsedJson) readStructuralIndices(r io.Reader, size int) error {
+	s := newStage1State()
+	cur := window{line: 1, column: 1}
+	var msg []byte
+	leading := true
+
+	// Comments are blanked in place up to this offset in msg.
+	var comments commentState
+	blanked := 0
+
+	for eof, first := false, true; !eof; first = false {
+		// Keep the unprocessed input and the start of a stripped index,
+		// which is sent to stage 2 with the next index buffer.
+		cut := uint64(s.offset)
+		if s.stripped_index != ^uint64(0) {
+			if pos := uint64(s.offset) + s.position + s.stripped_index; pos < cut {
+				cut = pos
+			}
+		}
+		if pj.utf8Mode == UTF8Reject && uint64(s.utf8Validated) < cut {
+			cut = uint64(s.utf8Validated)
+		}
+		if comments.comment == '*' {
+			// Keep the start of a block comment, in case it isn't closed.
+			if pos := comments.start - cur.offset; pos < cut {
+				cut = pos
+			}
+		}
+		keep := msg[cut:]
+
+		// Read at least as much as we keep, so long strings are not copied over and over.
+		grow := size
+		if len(keep) > grow {
+			grow = len(keep)
+		}
+		if first && grow < len(utf8BOM) {
+			// Read enough to recognize a byte order mark.
+			grow = len(utf8BOM)
+		}
+		next := make([]byte, len(keep), len(keep)+grow)
+		copy(next, keep)
+		n, err := io.ReadFull(r, next[len(keep):cap(next)])
+		next = next[:len(keep)+n]
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			eof = true
+		default:
+			return err
+		}
+
+		cur.advance(msg[:cut])
+		s.offset -= int(cut)
+		if pj.utf8Mode == UTF8Reject {
+			s.utf8Validated -= int(cut)
+		}
+		blanked -= int(cut)
+		if first && pj.lenient&LenientBOM != 0 && bytes.HasPrefix(next, utf8BOM) {
+			cur.advance(next[:len(utf8BOM)])
+			next = next[len(utf8BOM):]
+		}
+		if pj.lenient&LenientComments != 0 {
+			if first {
+				comments = commentState{offset: cur.offset}
+			}
+			blanked += comments.blank(next[blanked:], eof)
+			if eof && comments.comment == '*' {
+				return cur.newParseError(next, comments.start-cur.offset, ErrorKindUnclosedComment)
+			}
+		} else {
+			blanked = len(next)
+		}
+		if leading {
+			// Skip whitespace before the document,
+			// but keep the start of a block comment.
+			trimmed := blanked - len(bytes.TrimLeft(next[:blanked], " \t\n\r"))
+			leading = trimmed == blanked
+			if comments.comment == '*' {
+				if pos := int(comments.start - cur.offset); pos < trimmed {
+					trimmed = pos
+				}
+			}
+			cur.advance(next[:trimmed])
+			next = next[trimmed:]
+			blanked -= trimmed
+		}
+		if eof {
+			// Leave out whitespace after the document.
+			trimmed := len(bytes.TrimRight(next, " \t\n\r"))
+			if trimmed < s.offset {
+				trimmed = s.offset
+			}
+			next = next[:trimmed]
+			if blanked > trimmed {
+				blanked = trimmed
+			}
+		}
+
+		w := cur
+		w.msg = next
+		w.shift = cut
+		if s.window != nil {
+			// Stage 2 has not seen the previous window.
+			w.shift += s.window.shift
+		}
+		s.window = &w
+		msg = next
+
+		start := s.offset
+		inString, escaped := s.prev_iter_inside_quote != 0, s.prev_iter_ends_odd_backslash != 0
+		// A slash at the end of msg may start a comment, so it isn't processed yet.
+		// Whitespace before a block comment at the start is kept until the comment ends.
+		view := msg[:blanked]
+		if leading {
+			view = nil
+		}
+		pj.findStructuralIndicesIn(&s, view, eof)
+		if pj.errStage1 != nil {
+			cur.adjust(pj.errStage1)
+			return pj.errStage1
+		}
+		if s.error_mask != 0 {
+			pos := start + controlCharacterIndex(msg[start:s.offset], inString, escaped)
+			return cur.newParseError(msg, uint64(pos), ErrorKindControlCharacter)
+		}
+	}
+
+	switch {
+	case s.prev_iter_inside_quote != 0:
+		// The unclosed string starts at the last structural character.
+		return cur.newParseError(msg, uint64(s.offset)+s.position, ErrorKindUnclosedString)
+	case s.indexTotal == 0:
+		return cur.newParseError(msg, uint64(len(msg)), ErrorKindUnexpectedEnd)
+	}
+	return nil
+}
+
+// newParseError returns a stage 1 error at offset in msg, located in the input.
+func (w *window) newParseError(msg []byte, offset uint64, kind ErrorKind) *ParseError {
+	err := newParseError(msg, offset, 1, kind)
+	w.adjust(err)
+	return err
+}
+
+// controlCharacterIndex returns the index of the first control character inside a string in b.
+// inString and escaped is the state at the start of b.
+func controlCharacterIndex(b []byte, inString, escaped bool) int {
+	for i, c := range b {
+		if inString && c < 0x20 {
+			return i
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		}
+	}
+	return len(b)
+}
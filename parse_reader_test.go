@@ -0,0 +1,247 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// parseReaderSize parses b with ParseReader using windows of the specified size.
+func parseReaderSize(b []byte, size int, opts ...ParserOption) (*ParsedJson, error) {
+	pj, err := newInternalParsedJson(nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Return short reads to move the window boundaries around.
+	if err := pj.parseReader(iotest.HalfReader(bytes.NewReader(b)), size); err != nil {
+		return nil, err
+	}
+	return &pj.ParsedJson, nil
+}
+
+func testParseReaderSame(t *testing.T, name string, msg []byte, sizes []int) {
+	t.Helper()
+	want, err := Parse(msg, nil)
+	if err != nil {
+		t.Fatal(name, err)
+	}
+	for _, size := range sizes {
+		got, err := parseReaderSize(msg, size)
+		if err != nil {
+			t.Fatalf("%s (size %d): %v", name, size, err)
+		}
+		if len(got.Message) != 0 {
+			t.Errorf("%s (size %d): message should not be kept", name, size)
+		}
+		if !equalUint64s(got.Tape, want.Tape) || !bytes.Equal(got.Strings.B, want.Strings.B) {
+			t.Fatalf("%s (size %d): mismatch\ngot:  %s\nwant: %s", name, size, toJSON(t, got), toJSON(t, want))
+		}
+	}
+}
+
+func equalUint64s(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseReader(t *testing.T) {
+	for _, tt := range testCases {
+		msg := loadCompressed(t, tt.name)
+		sizes := []int{1000, 64 << 10}
+		if !testing.Short() {
+			sizes = append(sizes, 64, 100)
+		}
+		testParseReaderSame(t, tt.name, msg, sizes)
+	}
+
+	pj, err := ParseReader(strings.NewReader(" \n{\"a\":[1,\"b\",true]}\n "), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(toJSON(t, pj)), `{"a":[1,"b",true]}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	// Reuse the result.
+	pj, err = ParseReader(strings.NewReader("[1,2,3]"), pj, WithCopyStrings(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(toJSON(t, pj)), `[1,2,3]`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	p, err := NewParser(WithCopyStrings(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, err := p.ParseReader(strings.NewReader(`{"a":"b"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(toJSON(t, dst)), `{"a":"b"}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	// The option must still apply to Parse.
+	msg := []byte(`{"a":"b"}`)
+	dst, err = p.Parse(msg, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dst.Strings.B) != 0 {
+		t.Fatalf("strings should not be copied: %q", dst.Strings.B)
+	}
+}
+
+// TestParseReaderBoundaries checks values crossing window boundaries.
+func TestParseReaderBoundaries(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	values := []func() string{
+		func() string { return `"` + strings.Repeat(`\\`, rng.Intn(100)) + `"` },
+		func() string { return fmt.Sprint(rng.Int63()) },
+		func() string { return fmt.Sprint(rng.NormFloat64()) },
+		func() string { return "true" },
+		func() string { return "null" },
+		func() string {
+			return `"` + strings.Repeat(`\\`, rng.Intn(50)) + `\"` + strings.Repeat("x", rng.Intn(300)) + `"`
+		},
+		func() string { return `"` + strings.Repeat("\\u00e6", rng.Intn(50)) + `"` },
+		func() string { return `"` + strings.Repeat("ø", rng.Intn(50)) + `"` },
+		func() string { return "{}" },
+		func() string { return "[]" },
+	}
+	for i := 0; i < 50; i++ {
+		var sb strings.Builder
+		sb.WriteString(strings.Repeat(" ", rng.Intn(200)))
+		sb.WriteString("[")
+		for j := rng.Intn(200); j >= 0; j-- {
+			v := values[rng.Intn(len(values))]()
+			if rng.Intn(2) == 0 {
+				v = fmt.Sprintf("{%q:%s}", fmt.Sprint("key", j), v)
+			}
+			sb.WriteString(v)
+			sb.WriteString(strings.Repeat(" ", rng.Intn(3)))
+			if j > 0 {
+				sb.WriteString(",\n")
+			}
+		}
+		sb.WriteString("]")
+		sb.WriteString(strings.Repeat("\n", rng.Intn(200)))
+		testParseReaderSame(t, fmt.Sprint("input ", i), []byte(sb.String()), []int{64 + rng.Intn(100), 500})
+	}
+
+	// Scalars at the root, also with the value at the end of the input.
+	for _, js := range []string{`"` + strings.Repeat("long string ", 100) + `"`, "-1.25e3", "false", "null", `""`} {
+		testParseReaderSame(t, js, []byte(js), []int{64, 100})
+		testParseReaderSame(t, js, []byte(strings.Repeat(" ", 100)+js+strings.Repeat(" ", 100)), []int{64, 100})
+	}
+}
+
+func TestParseReaderError(t *testing.T) {
+	// Put the errors in later windows.
+	prefix := "[" + strings.Repeat("1,\n", 100)
+	testCases := []struct {
+		name string
+		js   string
+		opts []ParserOption
+		want *ParseError // if different from Parse
+	}{
+		{name: "literal", js: prefix + `{"a":tru}]`},
+		{name: "number", js: prefix + `[1, 2.x]]`},
+		{name: "escape", js: prefix + "{\"a\":\n  \"\\q\"}]"},
+		{name: "control", js: prefix + "{\"a\":\"" + strings.Repeat("\\\\", 50) + "x\x01\"}]"},
+		{name: "control-escaped", js: prefix + "{\"a\":\"" + strings.Repeat("x", 60) + "\\\x01\"}]",
+			want: &ParseError{Offset: 306, Line: 101, Column: 6, Stage: 2, Kind: ErrorKindInvalidString}},
+		{name: "unclosed-string", js: prefix + `{"a":"abc`},
+		{name: "unexpected-end", js: prefix + `[1,2`},
+		{name: "unexpected-end-comma", js: prefix + `[1,2,`},
+		{name: "unexpected", js: prefix + `{"a" 1}]`},
+		{name: "trailing", js: prefix + `1]]`},
+		{name: "trailing-root", js: "[1,2]\n  x"},
+		{name: "empty", js: "   ",
+			want: &ParseError{Offset: 3, Line: 1, Column: 4, Stage: 1, Kind: ErrorKindUnexpectedEnd}},
+		{name: "depth", js: prefix + `[[[1]]]]`, opts: []ParserOption{WithMaxDepth(3)}},
+		{name: "utf8", js: prefix + "\"" + strings.Repeat("ø", 50) + "\xff\"]", opts: []ParserOption{WithUTF8Mode(UTF8Reject)}},
+		// The first error is returned, even if stage 1 has found another.
+		{name: "stage2-first", js: prefix + "tru, \"\x01\"]",
+			want: &ParseError{Offset: 301, Line: 101, Column: 1, Stage: 2, Kind: ErrorKindInvalidLiteral}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			want := tc.want
+			if want == nil {
+				_, err := Parse([]byte(tc.js), nil, tc.opts...)
+				if !errors.As(err, &want) {
+					t.Fatalf("want *ParseError, got %T: %v", err, err)
+				}
+			}
+			for _, size := range []int{64, 100, 1000} {
+				_, err := parseReaderSize([]byte(tc.js), size, tc.opts...)
+				var got *ParseError
+				if !errors.As(err, &got) {
+					t.Fatalf("want *ParseError, got %T: %v", err, err)
+				}
+				if tc.want == nil {
+					// Errors may be found in another stage.
+					got.Stage = want.Stage
+				}
+				if *got != *want {
+					t.Errorf("size %d: got %v, want %v", size, got, want)
+				}
+			}
+		})
+	}
+
+	r := iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("[1,2,3]")))
+	if _, err := ParseReader(r, nil); err != iotest.ErrTimeout {
+		t.Fatalf("got %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func BenchmarkParseReader(b *testing.B) {
+	for _, tt := range testCases {
+		b.Run(tt.name, func(b *testing.B) {
+			ref := loadCompressed(b, tt.name)
+			p, err := NewParser()
+			if err != nil {
+				b.Fatal(err)
+			}
+			var dst *ParsedJson
+			b.SetBytes(int64(len(ref)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dst, err = p.ParseReader(bytes.NewReader(ref), dst)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
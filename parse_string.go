@@ -0,0 +1,161 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+// parseStringValidateOnlyGeneric is the pure Go version of parseStringSimdValidateOnly.
+// buf must start with the opening quote.
+func parseStringValidateOnlyGeneric(buf []byte, maxStringSize, dstLength *uint64, needCopy *bool) bool {
+	*dstLength = 0
+	srcLength, _, ok := unescapeString(nil, buf[1:], *maxStringSize, dstLength, false)
+	if !ok {
+		return false
+	}
+	*needCopy = *needCopy || srcLength != *dstLength
+	return true
+}
+
+// parseStringGeneric is the pure Go version of parseStringSimd.
+// The unescaped string is appended to stringbuf.
+// buf must start with the opening quote.
+func parseStringGeneric(buf []byte, stringbuf *[]byte) bool {
+	dstLength := uint64(0)
+	_, dst, ok := unescapeString(*stringbuf, buf[1:], uint64(len(buf)), &dstLength, true)
+	*stringbuf = dst
+	return ok
+}
+
+// escapeMap contains the replacement for each valid single character escape sequence.
+var escapeMap = [256]byte{
+	'"':  '"',
+	'\\': '\\',
+	'/':  '/',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+}
+
+// unescapeString will unescape src until the terminating quote is found
+// and return the length of the escaped string, excluding the quote.
+// The unescaped length is added to dstLength.
+// If write is set the unescaped string is appended to dst.
+// Control characters are not checked, since stage 1 rejects those.
+func unescapeString(dst, src []byte, maxStringSize uint64, dstLength *uint64, write bool) (srcLength uint64, _ []byte, ok bool) {
+	if maxStringSize == 0 {
+		return 0, dst, false
+	}
+	i := 0
+	for {
+		// Find the next quote or backslash.
+		start := i
+		for i < len(src) && src[i] != '"' && src[i] != '\\' {
+			i++
+		}
+		if i >= len(src) || uint64(i) >= maxStringSize {
+			return 0, dst, false
+		}
+		*dstLength += uint64(i - start)
+		if write {
+			dst = append(dst, src[start:i]...)
+		}
+		if src[i] == '"' {
+			return uint64(i), dst, true
+		}
+
+		// Escape sequence.
+		if i+1 >= len(src) {
+			return 0, dst, false
+		}
+		if src[i+1] != 'u' {
+			c := escapeMap[src[i+1]]
+			if c == 0 {
+				return 0, dst, false
+			}
+			*dstLength++
+			if write {
+				dst = append(dst, c)
+			}
+			i += 2
+			continue
+		}
+		cp, ok := parseHex4(src[i+2:])
+		if !ok {
+			return 0, dst, false
+		}
+		i += 6
+		if cp&0xfc00 == 0xd800 {
+			// High surrogate, must be followed by another code point.
+			if i+1 >= len(src) || src[i] != '\\' || src[i+1] != 'u' {
+				return 0, dst, false
+			}
+			cp2, ok := parseHex4(src[i+2:])
+			if !ok || cp|cp2 > 0xffff {
+				return 0, dst, false
+			}
+			cp = (((cp - 0xd800) << 10) | (cp2 - 0xdc00)) + 0x10000
+			i += 6
+		}
+		if cp > 0x10ffff {
+			return 0, dst, false
+		}
+		// Code points are written as is, so unpaired surrogates are kept.
+		switch {
+		case cp < 0x80:
+			*dstLength++
+			if write {
+				dst = append(dst, byte(cp))
+			}
+		case cp < 0x800:
+			*dstLength += 2
+			if write {
+				dst = append(dst, byte(0xc0|cp>>6), byte(0x80|cp&0x3f))
+			}
+		case cp < 0x10000:
+			*dstLength += 3
+			if write {
+				dst = append(dst, byte(0xe0|cp>>12), byte(0x80|(cp>>6)&0x3f), byte(0x80|cp&0x3f))
+			}
+		default:
+			*dstLength += 4
+			if write {
+				dst = append(dst, byte(0xf0|cp>>18), byte(0x80|(cp>>12)&0x3f), byte(0x80|(cp>>6)&0x3f), byte(0x80|cp&0x3f))
+			}
+		}
+	}
+}
+
+// parseHex4 parses the 4 hex digits at the start of src.
+func parseHex4(src []byte) (v uint32, ok bool) {
+	if len(src) < 4 {
+		return 0, false
+	}
+	for _, c := range src[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c -= 'a' - 10
+		case c >= 'A' && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, false
+		}
+		v = v<<4 | uint32(c)
+	}
+	return v, true
+}
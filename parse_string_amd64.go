@@ -57,3 +57,17 @@ func parseStringSimd(buf []byte, stringbuf *[]byte) bool {
 
 	return res != 0
 }
+
+func (pj *internalParsedJson) parseStringValidateOnly(buf []byte, maxStringSize, dstLength *uint64, needCopy *bool) bool {
+	if !pj.simd {
+		return parseStringValidateOnlyGeneric(buf, maxStringSize, dstLength, needCopy)
+	}
+	return parseStringSimdValidateOnly(buf, maxStringSize, dstLength, needCopy)
+}
+
+func (pj *internalParsedJson) parseStringUnescape(buf []byte, stringbuf *[]byte) bool {
+	if !pj.simd {
+		return parseStringGeneric(buf, stringbuf)
+	}
+	return parseStringSimd(buf, stringbuf)
+}
@@ -0,0 +1,28 @@
+//go:build !amd64 || appengine || !gc || noasm
+// +build !amd64 appengine !gc noasm
+
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+func (pj *internalParsedJson) parseStringValidateOnly(buf []byte, maxStringSize, dstLength *uint64, needCopy *bool) bool {
+	return parseStringValidateOnlyGeneric(buf, maxStringSize, dstLength, needCopy)
+}
+
+func (pj *internalParsedJson) parseStringUnescape(buf []byte, stringbuf *[]byte) bool {
+	return parseStringGeneric(buf, stringbuf)
+}
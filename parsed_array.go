@@ -148,6 +148,22 @@ readArray:
 				return nil, errors.New("corrupt input: expected integer, but no more values")
 			}
 			dst = append(dst, float64(a.tape.Tape[a.off]))
+		case TagNumber:
+			text, err := a.numberText()
+			if err != nil {
+				return nil, err
+			}
+			val, err := parseNumberTextFloat(text)
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, val)
+		case TagDecimal:
+			mantissa, exp, err := a.decimal()
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, decimalFloat(mantissa, exp))
 		case TagArrayEnd:
 			break readArray
 		default:
@@ -199,7 +215,27 @@ readArray:
 				return nil, errors.New("unsigned integer value overflows int64")
 			}
 
-			dst = append(dst)
+			dst = append(dst, int64(val))
+		case TagNumber:
+			text, err := a.numberText()
+			if err != nil {
+				return nil, err
+			}
+			val, err := parseNumberTextInt(text)
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, val)
+		case TagDecimal:
+			mantissa, exp, err := a.decimal()
+			if err != nil {
+				return nil, err
+			}
+			val, err := decimalInt(mantissa, exp)
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, val)
 		case TagArrayEnd:
 			break readArray
 		default:
@@ -251,6 +287,26 @@ readArray:
 			}
 
 			dst = append(dst, a.tape.Tape[a.off])
+		case TagNumber:
+			text, err := a.numberText()
+			if err != nil {
+				return nil, err
+			}
+			val, err := parseNumberTextUint(text)
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, val)
+		case TagDecimal:
+			mantissa, exp, err := a.decimal()
+			if err != nil {
+				return nil, err
+			}
+			val, err := decimalUint(mantissa, exp)
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, val)
 		case TagArrayEnd:
 			break readArray
 		default:
@@ -261,6 +317,51 @@ readArray:
 	return dst, nil
 }
 
+// numberText returns the text of the TagNumber before a.off.
+func (a *Array) numberText() ([]byte, error) {
+	if len(a.tape.Tape) <= a.off {
+		return nil, errors.New("corrupt input: expected number, but no more values")
+	}
+	return a.tape.stringByteAt(a.tape.Tape[a.off-1]&JSONVALUEMASK, a.tape.Tape[a.off])
+}
+
+// decimal returns the TagDecimal before a.off.
+func (a *Array) decimal() (mantissa int64, exp int32, err error) {
+	if len(a.tape.Tape) <= a.off {
+		return 0, 0, errors.New("corrupt input: expected decimal, but no more values")
+	}
+	return int64(a.tape.Tape[a.off]), int32(uint32(a.tape.Tape[a.off-1])), nil
+}
+
+// AsDecimal returns the array values as decimals, mantissas[i] * 10^exps[i].
+// See Iter.Decimal for the supported types.
+func (a *Array) AsDecimal() (mantissas []int64, exps []int32, err error) {
+	// Estimate length
+	lenEst := (len(a.tape.Tape) - a.off - 1) / 2
+	if lenEst < 0 {
+		lenEst = 0
+	}
+	mantissas = make([]int64, 0, lenEst)
+	exps = make([]int32, 0, lenEst)
+	i := a.Iter()
+	var elem Iter
+	for {
+		t, err := i.AdvanceIter(&elem)
+		if err != nil {
+			return nil, nil, err
+		}
+		if t == TypeNone {
+			return mantissas, exps, nil
+		}
+		mantissa, exp, err := elem.Decimal()
+		if err != nil {
+			return nil, nil, err
+		}
+		mantissas = append(mantissas, mantissa)
+		exps = append(exps, exp)
+	}
+}
+
 // AsString returns the array values as a slice of strings.
 // No conversion is done.
 func (a *Array) AsString() ([]string, error) {
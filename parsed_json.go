@@ -17,9 +17,11 @@
 package simdjson
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 )
 
@@ -29,6 +31,7 @@ const JSONTAGMASK = 0xff << JSONTAGOFFSET
 const STRINGBUFBIT = 0x80_0000_0000_0000
 const STRINGBUFMASK = 0x7fffffffffffff
 
+// maxdepth is the initial capacity of the scope stack.
 const maxdepth = 128
 
 // FloatFlags are flags recorded when converting floats.
@@ -68,6 +71,14 @@ type ParsedJson struct {
 
 	// allows to reuse the internal structures without exposing it.
 	internal *internalParsedJson
+
+	// location of the roots in Message, and of Message in the input.
+	roots    []rootLocation
+	rootBase RootInfo
+
+	// tape offsets of the roots, and of the first root when pj is a Slice.
+	rootTape  []uint64
+	tapeStart int
 }
 
 const indexSlots = 16
@@ -78,6 +89,7 @@ type indexChan struct {
 	index   int
 	length  int
 	indexes *[indexSize]uint32
+	window  *window // non-nil if the indexes are for a new window of the input
 }
 
 type internalParsedJson struct {
@@ -89,12 +101,32 @@ type internalParsedJson struct {
 	buffers               [indexSlots][indexSize]uint32
 	buffersOffset         uint64
 	ndjson                uint64
+	separator             rootSeparator
 	copyStrings           bool
+	simd                  bool
+	maxDepth              int
+	utf8Mode              UTF8Mode
+	lenient               LenientFlags
+	duplicateKeys         DuplicateKeys
+	bigNumbers            bool
+	decimals              bool
+	numbersAsText         bool
+	objectKeys            []objectKeys // indexed by depth
+	projection            *projection
+	projectionScopes      []projectionScope // indexed by depth
+	skipScopes            []byte            // objects and arrays open in a value that is not kept
+	skipInvalid           bool
+	skipReport            func(err *RecordError)
+	onDemand              bool
+	structurals           []uint32 // message offsets of structural characters when parsing on demand
+	errStage1             *ParseError
+	errStage2             *ParseError
+	window                *window
 }
 
 // Iter returns a new Iter.
 func (pj *ParsedJson) Iter() Iter {
-	return Iter{tape: *pj}
+	return Iter{tape: *pj, off: pj.tapeStart}
 }
 
 // stringAt returns a string at a specific offset in the stringbuffer.
@@ -123,7 +155,7 @@ func (pj *ParsedJson) stringByteAt(offset, length uint64) ([]byte, error) {
 // This will usually be an object or an array.
 // If the callback returns a non-nil error parsing stops and the errors is returned.
 func (pj *ParsedJson) ForEach(fn func(i Iter) error) error {
-	i := Iter{tape: *pj}
+	i := pj.Iter()
 	var elem Iter
 	for {
 		t, err := i.AdvanceIter(&elem)
@@ -139,11 +171,13 @@ func (pj *ParsedJson) ForEach(fn func(i Iter) error) error {
 
 // Clone returns a deep clone of the ParsedJson.
 // If a nil destination is sent a new will be created.
+// Cloning a Slice only copies the tape of its roots.
 func (pj *ParsedJson) Clone(dst *ParsedJson) *ParsedJson {
+	tape := pj.Tape[pj.tapeStart:]
 	if dst == nil {
 		dst = &ParsedJson{
 			Message:  make([]byte, len(pj.Message)),
-			Tape:     make([]uint64, len(pj.Tape)),
+			Tape:     make([]uint64, len(tape)),
 			Strings:  &TStrings{make([]byte, len(pj.Strings.B))},
 			internal: nil,
 		}
@@ -151,8 +185,8 @@ func (pj *ParsedJson) Clone(dst *ParsedJson) *ParsedJson {
 		if cap(dst.Message) < len(pj.Message) {
 			dst.Message = make([]byte, len(pj.Message))
 		}
-		if cap(dst.Tape) < len(pj.Tape) {
-			dst.Tape = make([]uint64, len(pj.Tape))
+		if cap(dst.Tape) < len(tape) {
+			dst.Tape = make([]uint64, len(tape))
 		}
 		if dst.Strings == nil {
 			dst.Strings = &TStrings{make([]byte, len(pj.Strings.B))}
@@ -161,12 +195,19 @@ func (pj *ParsedJson) Clone(dst *ParsedJson) *ParsedJson {
 		}
 	}
 	dst.internal = nil
-	dst.Tape = dst.Tape[:len(pj.Tape)]
-	copy(dst.Tape, pj.Tape)
+	dst.Tape = dst.Tape[:len(tape)]
+	copy(dst.Tape, tape)
 	dst.Message = dst.Message[:len(pj.Message)]
 	copy(dst.Message, pj.Message)
 	dst.Strings.B = dst.Strings.B[:len(pj.Strings.B)]
 	copy(dst.Strings.B, pj.Strings.B)
+	dst.roots = append(dst.roots[:0], pj.roots...)
+	dst.rootBase = pj.rootBase
+	dst.rootTape = append(dst.rootTape[:0], pj.rootTape...)
+	dst.tapeStart = 0
+	if pj.tapeStart > 0 {
+		rebaseTape(dst.Tape, dst.rootTape, uint64(pj.tapeStart))
+	}
 	return dst
 }
 
@@ -249,7 +290,7 @@ func (i *Iter) moveToEnd() {
 func (i *Iter) calcNext(into bool) {
 	i.addNext = 0
 	switch i.t {
-	case TagInteger, TagUint, TagFloat, TagString:
+	case TagInteger, TagUint, TagFloat, TagString, TagNumber, TagDecimal:
 		i.addNext = 1
 	case TagRoot, TagObjectStart, TagArrayStart:
 		if !into {
@@ -342,6 +383,7 @@ func (i *Iter) MarshalJSON() ([]byte, error) {
 // MarshalJSONBuffer will marshal the remaining scope of the iterator including the current value.
 // An optional buffer can be provided for fewer allocations.
 // Output will be appended to the destination.
+// Non-finite floats are written as null.
 func (i *Iter) MarshalJSONBuffer(dst []byte) ([]byte, error) {
 	var tmpBuf []byte
 
@@ -429,10 +471,27 @@ writeloop:
 			if err != nil {
 				return nil, err
 			}
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				// Only parsed with LenientNonFinite, write null like JSON.stringify.
+				dst = append(dst, []byte("null")...)
+				break
+			}
 			dst, err = appendFloat(dst, v)
 			if err != nil {
 				return nil, err
 			}
+		case TagNumber:
+			text, err := i.numberText()
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, text...)
+		case TagDecimal:
+			mantissa, exp, err := i.Decimal()
+			if err != nil {
+				return nil, err
+			}
+			dst = appendDecimal(dst, mantissa, exp)
 		case TagNull:
 			dst = append(dst, []byte("null")...)
 		case TagBoolTrue:
@@ -521,6 +580,15 @@ func (i *Iter) Float() (float64, error) {
 		}
 		v := i.tape.Tape[i.off]
 		return float64(v), nil
+	case TagNumber:
+		text, err := i.numberText()
+		if err != nil {
+			return 0, err
+		}
+		return parseNumberTextFloat(text)
+	case TagDecimal:
+		mantissa, exp, err := i.Decimal()
+		return decimalFloat(mantissa, exp), err
 	default:
 		return 0, fmt.Errorf("unable to convert type %v to float", i.t)
 	}
@@ -549,6 +617,19 @@ func (i *Iter) FloatFlags() (float64, FloatFlags, error) {
 		}
 		v := i.tape.Tape[i.off]
 		return float64(v), 0, nil
+	case TagNumber:
+		text, err := i.numberText()
+		if err != nil {
+			return 0, 0, err
+		}
+		v, err := parseNumberTextFloat(text)
+		if err != nil || !isIntegerText(text) {
+			return v, 0, err
+		}
+		return v, FloatFlags(FloatOverflowedInteger), nil
+	case TagDecimal:
+		mantissa, exp, err := i.Decimal()
+		return decimalFloat(mantissa, exp), 0, err
 	default:
 		return 0, 0, fmt.Errorf("unable to convert type %v to float", i.t)
 	}
@@ -558,7 +639,7 @@ func (i *Iter) FloatFlags() (float64, FloatFlags, error) {
 // Attempting to change other types will return an error.
 func (i *Iter) SetFloat(v float64) error {
 	switch i.t {
-	case TagFloat, TagInteger, TagUint, TagString:
+	case TagFloat, TagInteger, TagUint, TagString, TagNumber, TagDecimal:
 		i.tape.Tape[i.off-1] = uint64(TagFloat) << JSONTAGOFFSET
 		i.tape.Tape[i.off] = math.Float64bits(v)
 		i.t = TagFloat
@@ -599,6 +680,18 @@ func (i *Iter) Int() (int64, error) {
 			return 0, errors.New("unsigned integer value overflows int64")
 		}
 		return int64(v), nil
+	case TagNumber:
+		text, err := i.numberText()
+		if err != nil {
+			return 0, err
+		}
+		return parseNumberTextInt(text)
+	case TagDecimal:
+		mantissa, exp, err := i.Decimal()
+		if err != nil {
+			return 0, err
+		}
+		return decimalInt(mantissa, exp)
 	default:
 		return 0, fmt.Errorf("unable to convert type %v to int", i.t)
 	}
@@ -608,7 +701,7 @@ func (i *Iter) Int() (int64, error) {
 // Attempting to change other types will return an error.
 func (i *Iter) SetInt(v int64) error {
 	switch i.t {
-	case TagFloat, TagInteger, TagUint, TagString:
+	case TagFloat, TagInteger, TagUint, TagString, TagNumber, TagDecimal:
 		i.tape.Tape[i.off-1] = uint64(TagInteger) << JSONTAGOFFSET
 		i.tape.Tape[i.off] = uint64(v)
 		i.t = TagInteger
@@ -650,6 +743,18 @@ func (i *Iter) Uint() (uint64, error) {
 		}
 		v := i.tape.Tape[i.off]
 		return v, nil
+	case TagNumber:
+		text, err := i.numberText()
+		if err != nil {
+			return 0, err
+		}
+		return parseNumberTextUint(text)
+	case TagDecimal:
+		mantissa, exp, err := i.Decimal()
+		if err != nil {
+			return 0, err
+		}
+		return decimalUint(mantissa, exp)
 	default:
 		return 0, fmt.Errorf("unable to convert type %v to uint", i.t)
 	}
@@ -659,7 +764,7 @@ func (i *Iter) Uint() (uint64, error) {
 // Attempting to change other types will return an error.
 func (i *Iter) SetUInt(v uint64) error {
 	switch i.t {
-	case TagString, TagFloat, TagInteger, TagUint:
+	case TagString, TagFloat, TagInteger, TagUint, TagNumber, TagDecimal:
 		i.tape.Tape[i.off-1] = uint64(TagUint) << JSONTAGOFFSET
 		i.tape.Tape[i.off] = v
 		i.t = TagUint
@@ -703,7 +808,7 @@ func (i *Iter) SetString(v string) error {
 // Sending nil will add an empty string.
 func (i *Iter) SetStringBytes(v []byte) error {
 	switch i.t {
-	case TagString, TagFloat, TagInteger, TagUint:
+	case TagString, TagFloat, TagInteger, TagUint, TagNumber, TagDecimal:
 		i.cur = ((uint64(TagString) << JSONTAGOFFSET) | STRINGBUFBIT) | uint64(len(i.tape.Strings.B))
 		i.tape.Tape[i.off-1] = i.cur
 		i.tape.Tape[i.off] = uint64(len(v))
@@ -732,6 +837,8 @@ func (i *Iter) StringCvt() (string, error) {
 			return "", err
 		}
 		return floatToString(v)
+	case TagNumber, TagDecimal:
+		return i.NumberText()
 	case TagBoolFalse:
 		return "false", nil
 	case TagBoolTrue:
@@ -742,6 +849,175 @@ func (i *Iter) StringCvt() (string, error) {
 	return "", fmt.Errorf("cannot convert type %s to string", TagToType[i.t])
 }
 
+// NumberText returns the text of a number.
+// Numbers stored as text, see WithBigNumbers and WithNumbersAsText, are returned exactly as they appeared in the input.
+// Other numbers are formatted from their value.
+func (i *Iter) NumberText() (string, error) {
+	switch i.t {
+	case TagNumber:
+		text, err := i.numberText()
+		return string(text), err
+	case TagDecimal:
+		mantissa, exp, err := i.Decimal()
+		return string(appendDecimal(nil, mantissa, exp)), err
+	case TagInteger, TagUint, TagFloat:
+		return i.StringCvt()
+	}
+	return "", fmt.Errorf("unable to convert type %v to number", i.t)
+}
+
+// numberText returns the text of a TagNumber.
+func (i *Iter) numberText() ([]byte, error) {
+	if i.off >= len(i.tape.Tape) {
+		return nil, errors.New("corrupt input: no number offset on tape")
+	}
+	return i.tape.stringByteAt(i.cur, i.tape.Tape[i.off])
+}
+
+// BigInt returns the integer value of the next element.
+// Integers of any size are converted exactly, floats only if they are integral.
+func (i *Iter) BigInt() (*big.Int, error) {
+	switch i.t {
+	case TagInteger:
+		v, err := i.Int()
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(v), nil
+	case TagUint:
+		v, err := i.Uint()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(v), nil
+	case TagNumber:
+		text, err := i.numberText()
+		if err != nil {
+			return nil, err
+		}
+		if isIntegerText(text) {
+			if v, ok := new(big.Int).SetString(string(text), 10); ok {
+				return v, nil
+			}
+			return nil, fmt.Errorf("invalid number %s", text)
+		}
+		// Check the magnitude before converting exactly.
+		f, _, err := big.ParseFloat(string(text), 10, 64, big.ToNearestEven)
+		if err != nil {
+			return nil, err
+		}
+		if f.MantExp(nil) > maxBigIntBits {
+			return nil, fmt.Errorf("number %s is too large", text)
+		}
+		if f.Sign() != 0 && f.MantExp(nil) < 1 {
+			return nil, errors.New("number is not an integer")
+		}
+		if r, ok := new(big.Rat).SetString(string(text)); ok && r.IsInt() {
+			return r.Num(), nil
+		}
+		return nil, errors.New("number is not an integer")
+	case TagDecimal:
+		mantissa, exp, err := i.Decimal()
+		if err != nil {
+			return nil, err
+		}
+		return decimalBigInt(mantissa, exp)
+	case TagFloat:
+		f, err := i.BigFloat()
+		if err != nil {
+			return nil, err
+		}
+		v, acc := f.Int(nil)
+		if acc != big.Exact {
+			return nil, errors.New("float value is not an integer")
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("unable to convert type %v to integer", i.t)
+}
+
+// maxBigIntBits is the maximum size of integers returned by BigInt
+// for numbers in float notation.
+const maxBigIntBits = 1 << 20
+
+// BigFloat returns the value of the next element as an arbitrary-precision float.
+// Numbers stored as text are converted with enough precision to represent all their digits.
+func (i *Iter) BigFloat() (*big.Float, error) {
+	switch i.t {
+	case TagInteger:
+		v, err := i.Int()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Float).SetInt64(v), nil
+	case TagUint:
+		v, err := i.Uint()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Float).SetUint64(v), nil
+	case TagFloat:
+		v, err := i.Float()
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(v) {
+			return nil, errors.New("float value is NaN")
+		}
+		return big.NewFloat(v), nil
+	case TagNumber, TagDecimal:
+		text, err := i.NumberText()
+		if err != nil {
+			return nil, err
+		}
+		// At least 4 bits per digit.
+		prec := uint(len(text)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		v, _, err := big.ParseFloat(text, 10, prec, big.ToNearestEven)
+		return v, err
+	}
+	return nil, fmt.Errorf("unable to convert type %v to float", i.t)
+}
+
+// Decimal returns the value of the next element as mantissa * 10^exp.
+// Decimals, see WithDecimals, are returned exactly as they appeared in the input,
+// so 1.50 has mantissa 150 and exponent -2.
+// Integers and numbers stored as text are converted if they fit,
+// while floats are rejected, since they are not exact.
+func (i *Iter) Decimal() (mantissa int64, exp int32, err error) {
+	switch i.t {
+	case TagDecimal:
+		if i.off >= len(i.tape.Tape) {
+			return 0, 0, errors.New("corrupt input: expected decimal, but no more values on tape")
+		}
+		return int64(i.tape.Tape[i.off]), int32(uint32(i.cur)), nil
+	case TagInteger:
+		v, err := i.Int()
+		return v, 0, err
+	case TagUint:
+		v, err := i.Uint()
+		if err == nil && v > math.MaxInt64 {
+			err = errors.New("unsigned integer value overflows int64")
+		}
+		return int64(v), 0, err
+	case TagNumber:
+		text, err := i.numberText()
+		if err != nil {
+			return 0, 0, err
+		}
+		mantissa, exp, ok := parseDecimal(text)
+		if !ok {
+			return 0, 0, fmt.Errorf("number %s overflows decimal", text)
+		}
+		return mantissa, exp, nil
+	case TagFloat:
+		return 0, 0, errors.New("float value is not an exact decimal")
+	}
+	return 0, 0, fmt.Errorf("unable to convert type %v to decimal", i.t)
+}
+
 // Root returns the object embedded in root as an iterator
 // along with the type of the content of the first element of the iterator.
 // An optional destination can be supplied to avoid allocations.
@@ -867,6 +1143,9 @@ func (i *Iter) Interface() (interface{}, error) {
 		return i.Int()
 	case TypeFloat:
 		return i.Float()
+	case TypeNumber:
+		text, err := i.NumberText()
+		return json.Number(text), err
 	case TypeNull:
 		return nil, nil
 	case TypeArray:
@@ -967,6 +1246,9 @@ func (pj *ParsedJson) Reset() {
 	pj.Tape = pj.Tape[:0]
 	pj.Strings.B = pj.Strings.B[:0]
 	pj.Message = pj.Message[:0]
+	pj.roots = pj.roots[:0]
+	pj.rootTape = pj.rootTape[:0]
+	pj.tapeStart = 0
 }
 
 func (pj *ParsedJson) get_current_loc() uint64 {
@@ -1014,6 +1296,8 @@ const (
 	TagArrayStart  = Tag('[')
 	TagArrayEnd    = Tag(']')
 	TagRoot        = Tag('r')
+	TagNumber      = Tag('N') // Number stored as text, see WithBigNumbers and WithNumbersAsText
+	TagDecimal     = Tag('D') // Exact decimal, see WithDecimals
 	TagEnd         = Tag(0)
 )
 
@@ -1041,6 +1325,7 @@ const (
 	TypeObject
 	TypeArray
 	TypeRoot
+	TypeNumber
 )
 
 // String returns the type as a string.
@@ -1066,6 +1351,8 @@ func (t Type) String() string {
 		return "array"
 	case TypeRoot:
 		return "root"
+	case TypeNumber:
+		return "number"
 	}
 	return "(invalid)"
 }
@@ -1084,6 +1371,8 @@ var TagToType = [256]Type{
 	TagObjectStart: TypeObject,
 	TagArrayStart:  TypeArray,
 	TagRoot:        TypeRoot,
+	TagNumber:      TypeNumber,
+	TagDecimal:     TypeFloat,
 }
 
 // Type converts a tag to a type.
@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"log"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -103,9 +104,6 @@ var testCases = []struct {
 }
 
 func BenchmarkIter_MarshalJSONBuffer(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
 	for _, tt := range testCases {
 		b.Run(tt.name, func(b *testing.B) {
 			ref := loadCompressed(b, tt.name)
@@ -164,9 +162,6 @@ func BenchmarkGoMarshalJSON(b *testing.B) {
 }
 
 func TestPrintJson(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	msg := []byte(demo_json)
 	expected := `{"Image":{"Width":800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`
 
@@ -188,9 +183,6 @@ func TestPrintJson(t *testing.T) {
 }
 
 func TestExchange(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	input := `{"value": -20}`
 	pj, err := Parse([]byte(input), nil)
 	if err != nil {
@@ -240,9 +232,6 @@ func TestExchange(t *testing.T) {
 }
 
 func TestIter_SetNull(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	input := `{"0val":{"true":true,"false":false,"nullval":null},"1val":{"float":12.3456,"int":-42,"uint":9223372036854775808},"stringval":"initial value","array":[null,true,false,"astring",-42,9223372036854775808,1.23455]}`
 	tests := []struct {
 		want string
@@ -303,9 +292,6 @@ func TestIter_SetNull(t *testing.T) {
 }
 
 func TestIter_SetBool(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	input := `{"0val":{"true":true,"false":false,"nullval":null},"1val":{"float":12.3456,"int":-42,"uint":9223372036854775808},"stringval":"initial value","array":[null,true,false,"astring",-42,9223372036854775808,1.23455]}`
 	tests := []struct {
 		setTo bool
@@ -376,9 +362,6 @@ func TestIter_SetBool(t *testing.T) {
 }
 
 func TestIter_SetFloat(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	input := `{"0val":{"true":true,"false":false,"nullval":null},"1val":{"float":12.3456,"int":-42,"uint":9223372036854775808},"stringval":"initial value","array":[null,true,false,"astring",-42,9223372036854775808,1.23455]}`
 	tests := []struct {
 		setTo float64
@@ -451,9 +434,6 @@ func TestIter_SetFloat(t *testing.T) {
 }
 
 func TestIter_SetInt(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	input := `{"0val":{"true":true,"false":false,"nullval":null},"1val":{"float":12.3456,"int":-42,"uint":9223372036854775808},"stringval":"initial value","array":[null,true,false,"astring",-42,9223372036854775808,1.23455]}`
 	tests := []struct {
 		setTo int64
@@ -527,9 +507,6 @@ func TestIter_SetInt(t *testing.T) {
 }
 
 func TestIter_SetUInt(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	input := `{"0val":{"true":true,"false":false,"nullval":null},"1val":{"float":12.3456,"int":-42,"uint":9223372036854775808},"stringval":"initial value","array":[null,true,false,"astring",-42,9223372036854775808,1.23455]}`
 	tests := []struct {
 		setTo uint64
@@ -601,10 +578,38 @@ func TestIter_SetUInt(t *testing.T) {
 	}
 }
 
-func TestIter_SetString(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
+func TestArray_AsIntegerUint(t *testing.T) {
+	pj, err := Parse([]byte(`[1,2,3]`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter := pj.Iter()
+	iter.AdvanceInto()
+	_, root, err := iter.Root(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr, err := root.Array(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Store the values as unsigned integers.
+	elems := arr.Iter()
+	for n := uint64(10); elems.Advance() != TypeNone; n++ {
+		if err := elems.SetUInt(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := arr.AsInteger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{10, 11, 12}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
 	}
+}
+
+func TestIter_SetString(t *testing.T) {
 	input := `{"0val":{"true":true,"false":false,"nullval":null},"1val":{"float":12.3456,"int":-42,"uint":9223372036854775808},"stringval":"initial value","array":[null,true,false,"astring",-42,9223372036854775808,1.23455]}`
 	tests := []struct {
 		setTo string
@@ -679,9 +684,6 @@ func TestIter_SetString(t *testing.T) {
 }
 
 func TestIter_SetStringBytes(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	input := `{"0val":{"true":true,"false":false,"nullval":null},"1val":{"float":12.3456,"int":-42,"uint":9223372036854775808},"stringval":"initial value","array":[null,true,false,"astring",-42,9223372036854775808,1.23455]}`
 	tests := []struct {
 		setTo []byte
@@ -756,11 +758,6 @@ func TestIter_SetStringBytes(t *testing.T) {
 }
 
 func ExampleIter_FindElement() {
-	if !SupportedCPU() {
-		// Fake it
-		fmt.Println("int\n100 <nil>")
-		return
-	}
 	input := `{
     "Image":
     {
@@ -806,11 +803,6 @@ func ExampleIter_FindElement() {
 }
 
 func ExampleParsedJson_ForEach() {
-	if !SupportedCPU() {
-		// Fake results
-		fmt.Println("Got iterator for type: object\nFound element: URL Type: string Value: http://example.com/example.gif")
-		return
-	}
 
 	// Parse JSON:
 	pj, err := Parse([]byte(`{"Image":{"URL":"http://example.com/example.gif"}}`), nil)
@@ -32,6 +32,7 @@ type Object struct {
 
 // Map will unmarshal into a map[string]interface{}
 // See Iter.Interface() for a reference on value types.
+// If a key is duplicated, the last value is kept.
 func (o *Object) Map(dst map[string]interface{}) (map[string]interface{}, error) {
 	if dst == nil {
 		dst = make(map[string]interface{})
@@ -94,6 +95,8 @@ func (o *Object) Parse(dst *Elements) (*Elements, error) {
 // The method will return nil if the element cannot be found.
 // This should only be used to locate a single key where the object is no longer needed.
 // The object will not be advanced.
+// If a key is duplicated, the first match is returned.
+// Use WithDuplicateKeys to reject or remove duplicates when parsing.
 func (o *Object) FindKey(key string, dst *Element) *Element {
 	tmp := o.tape.Iter()
 	tmp.off = o.off
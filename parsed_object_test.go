@@ -7,9 +7,6 @@ import (
 )
 
 func TestObject_FindPath(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	tests := []struct {
 		name     string
 		path     []string
@@ -131,11 +128,6 @@ func TestObject_FindPath(t *testing.T) {
 }
 
 func ExampleObject_FindPath() {
-	if !SupportedCPU() {
-		// Fake it
-		fmt.Println("string\nhttp://www.example.com/image/481989943 <nil>")
-		return
-	}
 	input := `{
     "Image":
     {
@@ -36,7 +36,7 @@ const (
 	stringBits        = 14
 	stringSize        = 1 << stringBits
 	stringmask        = stringSize - 1
-	serializedVersion = 2
+	serializedVersion = 3
 )
 
 // Serializer allows to serialize parsed json and read it back.
@@ -228,7 +228,9 @@ func (s *Serializer) Serialize(dst []byte, pj ParsedJson) []byte {
 	//   - TagObjectEnd, TagArrayEnd: No value stored, derived from start.
 	//   - TagInteger, TagUint, TagFloat: 64 bits
 	// 	 - TagString: offset, length stored.
+	// 	 - TagNumber (v3): offset, length stored.
 	//   - tagFloatWithFlag (v2): Contains float parsing flag.
+	//   - TagDecimal (v3): exponent and mantissa, 64 bits each.
 	//
 	// If there are any values left as tag or value, it is considered invalid.
 
@@ -266,7 +268,9 @@ func (s *Serializer) Serialize(dst []byte, pj ParsedJson) []byte {
 	}
 
 	s.valuesBuf = s.valuesBuf[:0]
-	off := 0
+	// Offsets are stored relative to the entry, so a Slice can be serialized from its first root.
+	off := pj.tapeStart
+	tapeSize := len(pj.Tape) - pj.tapeStart
 	tagsOff := 0
 	var tmp [8]byte
 	rawValues := 0
@@ -287,7 +291,7 @@ func (s *Serializer) Serialize(dst []byte, pj ParsedJson) []byte {
 		payload := entry & JSONVALUEMASK
 
 		switch ntype {
-		case TagString:
+		case TagString, TagNumber:
 			sb, err := pj.stringByteAt(payload, pj.Tape[off+1])
 			if err != nil {
 				panic(err)
@@ -320,6 +324,12 @@ func (s *Serializer) Serialize(dst []byte, pj ParsedJson) []byte {
 				s.valuesBuf = append(s.valuesBuf, tmp[:]...)
 				off++
 			}
+		case TagDecimal:
+			binary.LittleEndian.PutUint64(tmp[:], payload)
+			s.valuesBuf = append(s.valuesBuf, tmp[:]...)
+			binary.LittleEndian.PutUint64(tmp[:], pj.Tape[off+1])
+			s.valuesBuf = append(s.valuesBuf, tmp[:]...)
+			off++
 		case TagNull, TagBoolTrue, TagBoolFalse:
 			// No value.
 		case TagObjectStart, TagArrayStart, TagRoot:
@@ -385,13 +395,13 @@ func (s *Serializer) Serialize(dst []byte, pj ParsedJson) []byte {
 		binary.PutUvarint(tmp[:], uint64(rawValues)) +
 		binary.PutUvarint(tmp[:], uint64(len(s.valuesCompBuf))) +
 		binary.PutUvarint(tmp[:], uint64(len(s.stringBuf))) +
-		binary.PutUvarint(tmp[:], uint64(len(pj.Tape)))
+		binary.PutUvarint(tmp[:], uint64(tapeSize))
 
 	n := binary.PutUvarint(tmp[:], uint64(1+len(s.sMsg)+len(s.tagsCompBuf)+len(s.valuesCompBuf)+varInts))
 	dst = append(dst, tmp[:n]...)
 
 	// Tape elements, uncompressed.
-	n = binary.PutUvarint(tmp[:], uint64(len(pj.Tape)))
+	n = binary.PutUvarint(tmp[:], uint64(tapeSize))
 	dst = append(dst, tmp[:n]...)
 
 	// Strings uncompressed size
@@ -421,7 +431,7 @@ func (s *Serializer) Serialize(dst []byte, pj ParsedJson) []byte {
 	dst = append(dst, tmp[:n]...)
 	dst = append(dst, s.valuesCompBuf...)
 	if false {
-		fmt.Println("strings:", len(pj.Strings.B)+len(pj.Message), "->", len(s.sMsg), "tags:", rawTags, "->", len(s.tagsCompBuf), "values:", rawValues, "->", len(s.valuesCompBuf), "Total:", len(pj.Message)+len(pj.Strings.B)+len(pj.Tape)*8, "->", len(dst))
+		fmt.Println("strings:", len(pj.Strings.B)+len(pj.Message), "->", len(s.sMsg), "tags:", rawTags, "->", len(s.tagsCompBuf), "values:", rawValues, "->", len(s.valuesCompBuf), "Total:", len(pj.Message)+len(pj.Strings.B)+tapeSize*8, "->", len(dst))
 	}
 
 	return dst
@@ -466,13 +476,18 @@ func (s *Serializer) Deserialize(src []byte, dst *ParsedJson) (*ParsedJson, erro
 	if v, err := br.ReadByte(); err != nil {
 		return dst, err
 	} else if v > serializedVersion {
-		// v2 reads v1.
+		// v3 reads v1 and v2.
 		return dst, errors.New("unknown version")
 	}
 
 	if dst == nil {
 		dst = &ParsedJson{}
 	}
+	// Roots are not located in the deserialized message.
+	dst.roots = dst.roots[:0]
+	dst.rootBase = RootInfo{}
+	dst.rootTape = dst.rootTape[:0]
+	dst.tapeStart = 0
 
 	// Comp size
 	if c, err := binary.ReadUvarint(br); err != nil {
@@ -585,7 +600,7 @@ func (s *Serializer) Deserialize(src []byte, dst *ParsedJson) (*ParsedJson, erro
 
 		tagDst := uint64(t) << 56
 		switch tag {
-		case TagString:
+		case TagString, TagNumber:
 			if len(values) < 16 {
 				return dst, fmt.Errorf("reading %v: no values left", tag)
 			}
@@ -613,6 +628,15 @@ func (s *Serializer) Deserialize(src []byte, dst *ParsedJson) (*ParsedJson, erro
 			dst.Tape[off+1] = binary.LittleEndian.Uint64(values[8:16])
 			values = values[16:]
 			off += 2
+		case TagDecimal:
+			// Exponent and mantissa.
+			if len(values) < 16 {
+				return dst, fmt.Errorf("reading %v: no values left", tag)
+			}
+			dst.Tape[off] = tagDst | binary.LittleEndian.Uint64(values[:8])&JSONVALUEMASK
+			dst.Tape[off+1] = binary.LittleEndian.Uint64(values[8:16])
+			values = values[16:]
+			off += 2
 		case TagNull, TagBoolTrue, TagBoolFalse, TagEnd:
 			dst.Tape[off] = tagDst
 			off++
@@ -668,6 +692,7 @@ func (s *Serializer) Deserialize(src []byte, dst *ParsedJson) (*ParsedJson, erro
 	if stringsErr != nil {
 		return dst, fmt.Errorf("reading strings: %w", stringsErr)
 	}
+	dst.rootTape = findRoots(dst.rootTape, dst.Tape, 0)
 	return dst, nil
 }
 
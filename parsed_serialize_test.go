@@ -23,10 +23,6 @@ import (
 )
 
 func BenchmarkSerialize(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
-
 	bench := func(b *testing.B, s *Serializer) {
 		for _, tt := range testCases {
 			b.Run(tt.name, func(b *testing.B) {
@@ -69,10 +65,6 @@ func BenchmarkSerialize(b *testing.B) {
 }
 
 func BenchmarkDeSerialize(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
-
 	bench := func(b *testing.B, s *Serializer) {
 		for _, tt := range testCases {
 			b.Run(tt.name, func(b *testing.B) {
@@ -125,10 +117,6 @@ func BenchmarkDeSerialize(b *testing.B) {
 }
 
 func BenchmarkSerializeNDJSON(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
-
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 
 	pj, err := ParseND(ndjson, nil)
@@ -168,10 +156,6 @@ func BenchmarkSerializeNDJSON(b *testing.B) {
 }
 
 func BenchmarkDeSerializeNDJSON(b *testing.B) {
-	if !SupportedCPU() {
-		b.SkipNow()
-	}
-
 	ndjson := loadFile("testdata/parking-citations-1M.json.zst")
 
 	pj, err := ParseND(ndjson, nil)
@@ -218,9 +202,6 @@ func BenchmarkDeSerializeNDJSON(b *testing.B) {
 }
 
 func TestDeSerializeNDJSON(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	if testing.Short() {
 		t.Skip("skipping... too long")
 	}
@@ -275,9 +256,6 @@ func TestDeSerializeNDJSON(t *testing.T) {
 }
 
 func TestDeSerializeJSON(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	test := func(t *testing.T, s *Serializer) {
 		for _, tt := range testCases {
 			org := loadCompressed(t, tt.name)
@@ -333,3 +311,33 @@ func TestDeSerializeJSON(t *testing.T) {
 		test(b, s)
 	})
 }
+
+func TestSerializeNumbers(t *testing.T) {
+	js := `{"a":123456789012345678901234567890,"b":[19.99,-1e-7,2]}`
+	want := `{"a":123456789012345678901234567890,"b":[19.99,-0.0000001,2]}`
+	pj, err := Parse([]byte(js), nil, WithBigNumbers(), WithDecimals())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []CompressMode{CompressNone, CompressFast, CompressDefault, CompressBest} {
+		s := NewSerializer()
+		s.CompressMode(mode)
+		output := s.Serialize(nil, *pj)
+		// Readers of earlier versions don't know TagNumber and TagDecimal.
+		if output[0] != serializedVersion || serializedVersion < 3 {
+			t.Fatalf("mode %d: got version %d", mode, output[0])
+		}
+		pj2, err := s.Deserialize(output, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(toJSON(t, pj2)); got != want {
+			t.Fatalf("mode %d: got %s, want %s", mode, got, want)
+		}
+
+		output[0] = serializedVersion + 1
+		if _, err := s.Deserialize(output, nil); err == nil {
+			t.Fatalf("mode %d: want error for unknown version", mode)
+		}
+	}
+}
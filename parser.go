@@ -0,0 +1,139 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"io"
+	"sync"
+)
+
+// Parser is a reusable parser.
+// It owns the buffers used while parsing and keeps them between calls,
+// so parsing with the same Parser will not allocate once the buffers have grown to fit the input.
+// The tape and strings of the result are kept in the supplied destination,
+// which should be reused for the same reason.
+// A Parser cannot be used concurrently.
+type Parser struct {
+	pj internalParsedJson
+}
+
+// NewParser returns a parser with the supplied options.
+func NewParser(opts ...ParserOption) (*Parser, error) {
+	p := &Parser{}
+	if err := p.pj.applyOptions(opts); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Parse a block of data and return the parsed JSON.
+// The tape and string buffers of dst will be reused if it is non-nil,
+// and the result will be stored in dst.
+// The returned value does not reference the parser,
+// so it can be kept after the parser is used again.
+// Invalid JSON is reported as a *ParseError.
+func (p *Parser) Parse(b []byte, dst *ParsedJson) (*ParsedJson, error) {
+	return p.parse(b, dst, separatorNone)
+}
+
+// ParseND will parse newline delimited JSON.
+// See Parse for how dst is used.
+func (p *Parser) ParseND(b []byte, dst *ParsedJson) (*ParsedJson, error) {
+	return p.parse(b, dst, separatorNewline)
+}
+
+// ParseConcatenated will parse concatenated JSON values.
+// See ParseConcatenated for details and Parse for how dst is used.
+func (p *Parser) ParseConcatenated(b []byte, dst *ParsedJson) (*ParsedJson, error) {
+	return p.parse(b, dst, separatorWhitespace)
+}
+
+// ParseSequence will parse a JSON text sequence as defined by RFC 7464.
+// See ParseSequence for details and Parse for how dst is used.
+func (p *Parser) ParseSequence(b []byte, dst *ParsedJson) (*ParsedJson, error) {
+	return p.parse(b, dst, separatorRecord)
+}
+
+// ParseReader will parse a single JSON document read from r.
+// See ParseReader for details and Parse for how dst is used.
+func (p *Parser) ParseReader(r io.Reader, dst *ParsedJson) (*ParsedJson, error) {
+	p.use(dst)
+	return p.result(dst, p.pj.parseReader(r, readerWindowSize))
+}
+
+func (p *Parser) parse(b []byte, dst *ParsedJson, sep rootSeparator) (*ParsedJson, error) {
+	p.use(dst)
+	return p.result(dst, p.pj.parseSeparated(b, sep))
+}
+
+// use will parse into the buffers of dst.
+func (p *Parser) use(dst *ParsedJson) {
+	if dst != nil {
+		p.pj.Tape = dst.Tape
+		p.pj.Strings = dst.Strings
+		p.pj.roots = dst.roots
+		p.pj.rootTape = dst.rootTape
+	}
+}
+
+// result stores the result of parsing in dst.
+func (p *Parser) result(dst *ParsedJson, err error) (*ParsedJson, error) {
+	parsed := p.pj.ParsedJson
+	// Don't keep references to the result.
+	p.pj.ParsedJson = ParsedJson{}
+	if err != nil {
+		return nil, err
+	}
+	if dst == nil {
+		dst = &ParsedJson{}
+	}
+	*dst = parsed
+	return dst, nil
+}
+
+// ParserPool is a pool of parsers with the same options.
+// It can be used concurrently, for example by HTTP handlers.
+type ParserPool struct {
+	pool sync.Pool
+}
+
+// NewParserPool returns a pool of parsers created with the supplied options.
+func NewParserPool(opts ...ParserOption) (*ParserPool, error) {
+	// Check options before returning.
+	if _, err := NewParser(opts...); err != nil {
+		return nil, err
+	}
+	pp := &ParserPool{}
+	pp.pool.New = func() interface{} {
+		p, _ := NewParser(opts...)
+		return p
+	}
+	return pp, nil
+}
+
+// Get returns a parser from the pool.
+// Return it with Put when done.
+func (pp *ParserPool) Get() *Parser {
+	return pp.pool.Get().(*Parser)
+}
+
+// Put returns a parser to the pool.
+// Only parsers returned by Get should be returned to the pool,
+// and it should not be used after it has been returned.
+func (pp *ParserPool) Put(p *Parser) {
+	pp.pool.Put(p)
+}
@@ -0,0 +1,211 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestParser(t *testing.T) {
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst *ParsedJson
+	var results [][]byte
+	for _, tt := range testCases {
+		ref := loadCompressed(t, tt.name)
+		want, err := Parse(ref, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst, err = p.Parse(ref, dst)
+		if err != nil {
+			t.Fatal(tt.name, err)
+		}
+		if !bytes.Equal(toJSON(t, want), toJSON(t, dst)) {
+			t.Fatal(tt.name, "mismatch")
+		}
+		// A result without destination must not be changed by later use of the parser.
+		pj, err := p.Parse(ref, nil)
+		if err != nil {
+			t.Fatal(tt.name, err)
+		}
+		results = append(results, toJSON(t, pj))
+		defer func(name string, pj *ParsedJson, want []byte) {
+			if !bytes.Equal(toJSON(t, pj), want) {
+				t.Error(name, "result changed")
+			}
+		}(tt.name, pj, results[len(results)-1])
+	}
+
+	nd := []byte("{\"a\":1}\n{\"b\":2}\n\n[3]\n")
+	dst, err = p.ParseND(nd, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(toJSON(t, dst)), "{\"a\":1}\n{\"b\":2}\n[3]"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if _, err := p.Parse([]byte("[1,2"), dst); err == nil {
+		t.Fatal("want error")
+	}
+	// The parser can still be used after an error.
+	if _, err := p.Parse([]byte("[1,2]"), dst); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParserOptions(t *testing.T) {
+	p, err := NewParser(WithMaxDepth(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Parse([]byte("[[1]]"), nil); err != nil {
+		t.Fatal(err)
+	}
+	// Options are kept between calls.
+	for i := 0; i < 2; i++ {
+		if _, err := p.Parse([]byte("[[[1]]]"), nil); err == nil {
+			t.Fatal("want error")
+		}
+	}
+	if _, err := NewParser(WithUTF8Mode(UTF8Replace + 1)); err == nil {
+		t.Fatal("want error")
+	}
+	if _, err := NewParserPool(WithUTF8Mode(UTF8Replace + 1)); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+func TestParserAllocs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	msg := []byte(demo_json)
+	p, err := NewParser(WithCopyStrings(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, err := p.Parse(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		dst, err = p.Parse(msg, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations, want 0", allocs)
+	}
+}
+
+func TestParserPool(t *testing.T) {
+	pp, err := NewParserPool(WithCopyStrings(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := loadCompressed(t, "twitter")
+	want, err := Parse(ref, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := toJSON(t, want)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dst *ParsedJson
+			for j := 0; j < 10; j++ {
+				p := pp.Get()
+				var err error
+				dst, err = p.Parse(ref, dst)
+				pp.Put(p)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !bytes.Equal(toJSON(t, dst), wantJSON) {
+					t.Error("mismatch")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func toJSON(t *testing.T, pj *ParsedJson) []byte {
+	t.Helper()
+	i := pj.Iter()
+	b, err := i.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func BenchmarkParser(b *testing.B) {
+	for _, tt := range testCases {
+		b.Run(tt.name, func(b *testing.B) {
+			ref := loadCompressed(b, tt.name)
+			p, err := NewParser()
+			if err != nil {
+				b.Fatal(err)
+			}
+			var dst *ParsedJson
+			b.SetBytes(int64(len(ref)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dst, err = p.Parse(ref, dst)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParserNumbersAsText(b *testing.B) {
+	for _, name := range []string{"canada", "citm_catalog", "twitter"} {
+		b.Run(name, func(b *testing.B) {
+			ref := loadCompressed(b, name)
+			p, err := NewParser(WithNumbersAsText())
+			if err != nil {
+				b.Fatal(err)
+			}
+			var dst *ParsedJson
+			b.SetBytes(int64(len(ref)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dst, err = p.Parse(ref, dst)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
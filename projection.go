@@ -0,0 +1,213 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+// projection is a node in the tree of paths kept by WithProjection.
+type projection struct {
+	// keys kept in an object at this path.
+	// A nil value keeps the whole value of the key.
+	keys map[string]*projection
+}
+
+// newProjection returns the tree of paths.
+// nil is returned if all values are kept.
+func newProjection(paths [][]string) *projection {
+	root := &projection{keys: make(map[string]*projection)}
+	for _, path := range paths {
+		if len(path) == 0 {
+			return nil
+		}
+		n := root
+		for i, key := range path {
+			child, ok := n.keys[key]
+			if ok && child == nil {
+				// A shorter path keeps the whole value.
+				break
+			}
+			if i == len(path)-1 {
+				n.keys[key] = nil
+				break
+			}
+			if !ok {
+				child = &projection{keys: make(map[string]*projection)}
+				n.keys[key] = child
+			}
+			n = child
+		}
+	}
+	return root
+}
+
+// projectionScope is the projection state of an object or array while it is parsed.
+type projectionScope struct {
+	node  *projection // nil if all values are kept
+	field *projection // projection of the value of the current key
+	array bool
+}
+
+// beginProjection starts the projection of an object or array.
+func (pj *internalParsedJson) beginProjection(array bool) {
+	depth := len(pj.containingScopeOffset)
+	for len(pj.projectionScopes) <= depth {
+		pj.projectionScopes = append(pj.projectionScopes, projectionScope{})
+	}
+	node := pj.projection
+	if depth > 2 {
+		// Arrays apply their projection to all elements.
+		parent := &pj.projectionScopes[depth-1]
+		node = parent.node
+		if !parent.array {
+			node = parent.field
+		}
+	}
+	pj.projectionScopes[depth] = projectionScope{node: node, array: array}
+}
+
+// projectKey checks if the value of the key that was last written to the tape is kept.
+// If not, the key is removed from the tape and false is returned.
+func (pj *internalParsedJson) projectKey() bool {
+	s := &pj.projectionScopes[len(pj.containingScopeOffset)]
+	if s.node == nil {
+		return true
+	}
+	var ok bool
+	s.field, ok = s.node.keys[string(pj.lastKey())]
+	if ok {
+		return true
+	}
+	loc := len(pj.Tape) - 2
+	if offset := pj.Tape[loc] & JSONVALUEMASK; offset&STRINGBUFBIT != 0 {
+		pj.Strings.B = pj.Strings.B[:offset&STRINGBUFMASK]
+	}
+	pj.Tape = pj.Tape[:loc]
+	return false
+}
+
+// skipValue validates the value at idx, which is not kept,
+// without writing it to the tape or copying its strings.
+// The index of the last structural character of the value is returned.
+// If the value is invalid pj.errStage2 is set and ok is false.
+func (pj *internalParsedJson) skipValue(idx uint64, maxScopes uint64) (_ uint64, ok, done bool) {
+	// Open objects and arrays of the value.
+	scopes := pj.skipScopes[:0]
+	kind := ErrorKindUnexpectedCharacter
+
+value:
+	switch c := pj.Message[idx]; c {
+	case '{', '[':
+		if uint64(len(pj.containingScopeOffset)+len(scopes)) >= maxScopes {
+			kind = ErrorKindDepthExceeded
+			goto fail
+		}
+		scopes = append(scopes, c)
+		if done, idx = updateChar(pj, idx); done {
+			goto end
+		}
+		if pj.Message[idx] == closing(c) {
+			scopes = scopes[:len(scopes)-1]
+			goto valueEnd
+		}
+		if c == '{' {
+			goto key
+		}
+		goto value
+	case '"':
+		if !pj.validString(idx, peekSize(pj)) {
+			kind = ErrorKindInvalidString
+			goto fail
+		}
+	case 't', 'f', 'n':
+		if !isValidTrueAtom(pj.Message[idx:]) && !isValidFalseAtom(pj.Message[idx:]) && !isValidNullAtom(pj.Message[idx:]) {
+			kind = ErrorKindInvalidLiteral
+			goto fail
+		}
+	default:
+		if _, nonFinite := pj.nonFinite(pj.Message[idx:]); nonFinite {
+			break
+		}
+		if c != '-' && (c < '0' || c > '9') {
+			goto fail
+		}
+		if !pj.validNumber(pj.Message[idx:]) {
+			kind = ErrorKindInvalidNumber
+			goto fail
+		}
+	}
+
+valueEnd:
+	if len(scopes) == 0 {
+		pj.skipScopes = scopes
+		return idx, true, false
+	}
+	if done, idx = updateChar(pj, idx); done {
+		goto end
+	}
+	switch pj.Message[idx] {
+	case ',':
+		if done, idx = updateChar(pj, idx); done {
+			goto end
+		}
+		if pj.Message[idx] == closing(scopes[len(scopes)-1]) && pj.lenient&LenientTrailingCommas != 0 {
+			scopes = scopes[:len(scopes)-1]
+			goto valueEnd
+		}
+		if scopes[len(scopes)-1] == '{' {
+			goto key
+		}
+		goto value
+	case closing(scopes[len(scopes)-1]):
+		scopes = scopes[:len(scopes)-1]
+		goto valueEnd
+	}
+	goto fail
+
+key:
+	if pj.Message[idx] != '"' {
+		goto fail
+	}
+	if !pj.validString(idx, peekSize(pj)) {
+		kind = ErrorKindInvalidString
+		goto fail
+	}
+	if done, idx = updateChar(pj, idx); done {
+		goto end
+	}
+	if pj.Message[idx] != ':' {
+		goto fail
+	}
+	if done, idx = updateChar(pj, idx); done {
+		goto end
+	}
+	goto value
+
+end:
+	pj.skipScopes = scopes
+	return idx, false, true
+
+fail:
+	pj.skipScopes = scopes
+	pj.errStage2 = newParseError(pj.Message, idx, 2, kind)
+	return idx, false, false
+}
+
+// closing returns the character that closes an object or array opened with c.
+func closing(c byte) byte {
+	if c == '{' {
+		return '}'
+	}
+	return ']'
+}
@@ -0,0 +1,156 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// RecordError describes an invalid record of newline delimited JSON
+// that was skipped because of WithSkipInvalidRecords.
+type RecordError struct {
+	// Line is the line number of the record, starting at 1.
+	Line int
+
+	// Start and End are the byte range of the record in the input,
+	// without surrounding whitespace.
+	// When streaming they are offsets from the start of the stream.
+	Start, End uint64
+
+	// Err is the error, located in the input like Start and End.
+	Err *ParseError
+}
+
+// Error returns a description of the error and the record.
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("invalid record at line %d, offset %d-%d: %v", e.Line, e.Start, e.End, e.Err)
+}
+
+// Unwrap returns the *ParseError of the record.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// parseRecords parses newline delimited JSON in msg, skipping invalid records.
+// input is msg before comments were blanked, which is used for line numbers,
+// and bom is the length of the byte order mark removed before it.
+// If msg cannot be parsed it is split at newlines and the parts are parsed again,
+// until the invalid records are found.
+// The valid records are then parsed from a copy of msg with the invalid records blanked,
+// so roots keep their location in the input.
+func (pj *internalParsedJson) parseRecords(input, msg []byte, bom uint64) error {
+	err := pj.parseStages(msg)
+	var perr *ParseError
+	if err == nil || !errors.As(err, &perr) {
+		return err
+	}
+	f := recordFinder{pj: pj, input: input, valid: append([]byte(nil), msg...), bom: bom, line: 1}
+	if err := f.findInvalid(msg, 0); err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(f.valid)) == 0 {
+		// No valid records.
+		pj.Message = f.valid
+		pj.initialize(0)
+		return nil
+	}
+	return pj.parseStages(f.valid)
+}
+
+// recordFinder finds the invalid records of a message.
+type recordFinder struct {
+	pj    *internalParsedJson
+	input []byte
+	valid []byte // copy of the message with invalid records blanked
+	bom   uint64
+
+	// line number at offset in input
+	line   int
+	offset uint64
+}
+
+// findInvalid blanks and reports the invalid records in msg,
+// which starts at offset in the message.
+func (f *recordFinder) findInvalid(msg []byte, offset uint64) error {
+	record := bytes.TrimSpace(msg)
+	if len(record) == 0 {
+		return nil
+	}
+	err := f.pj.parseStages(msg)
+	if err == nil {
+		return nil
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		return err
+	}
+
+	// Split at the newline closest to the middle.
+	half := len(msg) / 2
+	mid := bytes.IndexByte(msg[half:], '\n')
+	if mid >= 0 {
+		mid += half
+	} else {
+		mid = bytes.LastIndexByte(msg[:half], '\n')
+	}
+	if mid < 0 {
+		f.skip(offset+uint64(cap(msg)-cap(record)), uint64(len(record)), perr)
+		return nil
+	}
+	if err := f.findInvalid(msg[:mid], offset); err != nil {
+		return err
+	}
+	return f.findInvalid(msg[mid+1:], offset+uint64(mid)+1)
+}
+
+// skip blanks and reports the invalid record at start.
+// Records are found in order, so lines are only counted once.
+func (f *recordFinder) skip(start, length uint64, err *ParseError) {
+	for i := start; i < start+length; i++ {
+		f.valid[i] = ' '
+	}
+	f.line += bytes.Count(f.input[f.offset:start], []byte{'\n'})
+	f.offset = start
+	if f.pj.skipReport != nil {
+		f.pj.skipReport(&RecordError{
+			Line:  f.line,
+			Start: f.bom + start,
+			End:   f.bom + start + length,
+			Err:   f.locate(err, start),
+		})
+	}
+}
+
+// locate returns a copy of err, found in the record at start,
+// with the location in the input.
+// The record starts at f.line.
+func (f *recordFinder) locate(err *ParseError, start uint64) *ParseError {
+	located := *err
+	if located.Line == 1 {
+		before := f.input[:start]
+		column := len(before) - bytes.LastIndexByte(before, '\n')
+		if f.line == 1 {
+			column += int(f.bom)
+		}
+		located.Column += column - 1
+	}
+	located.Line += f.line - 1
+	located.Offset += f.bom + start
+	return &located
+}
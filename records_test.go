@@ -0,0 +1,173 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSkipInvalidRecords(t *testing.T) {
+	const input = "{\"a\":1}\n{\"a\":tru}\n\n  [2]\n  [3,]  \n\"x\"\n{\"b\":\n4"
+	want := []interface{}{map[string]interface{}{"a": int64(1)}, []interface{}{int64(2)}, "x", int64(4)}
+	wantSkipped := []RecordError{
+		{Line: 2, Start: 8, End: 17, Err: &ParseError{Kind: ErrorKindInvalidLiteral, Offset: 13, Line: 2, Column: 6}},
+		{Line: 5, Start: 27, End: 31, Err: &ParseError{Kind: ErrorKindUnexpectedCharacter, Offset: 30, Line: 5, Column: 6}},
+		{Line: 7, Start: 38, End: 43, Err: &ParseError{Kind: ErrorKindUnexpectedEnd, Offset: 43, Line: 7, Column: 6}},
+	}
+	if _, err := ParseND([]byte(input), nil); err == nil {
+		t.Fatal("expected error without skipping")
+	}
+
+	var skipped []*RecordError
+	report := func(e *RecordError) { skipped = append(skipped, e) }
+	check := func(t *testing.T, pj *ParsedJson, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkRoots(t, pj, want)
+		if len(skipped) != len(wantSkipped) {
+			t.Fatalf("got %d skipped records, want %d", len(skipped), len(wantSkipped))
+		}
+		for i, got := range skipped {
+			w := wantSkipped[i]
+			if got.Line != w.Line || got.Start != w.Start || got.End != w.End ||
+				got.Err.Kind != w.Err.Kind || got.Err.Offset != w.Err.Offset ||
+				got.Err.Line != w.Err.Line || got.Err.Column != w.Err.Column {
+				t.Errorf("record %d: got %v, want %v", i, got, &w)
+			}
+			if !errors.Is(got, got.Err) {
+				t.Errorf("record %d: does not unwrap to ParseError", i)
+			}
+			if rec := input[got.Start:got.End]; strings.Contains(rec, "\n") || strings.TrimSpace(rec) != rec {
+				t.Errorf("record %d: bad range %q", i, rec)
+			}
+		}
+		skipped = nil
+	}
+	pj, err := ParseND([]byte(input), nil, WithSkipInvalidRecords(report))
+	check(t, pj, err)
+
+	p, err := NewParser(WithSkipInvalidRecords(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pj, err = p.ParseND([]byte(input), nil)
+	check(t, pj, err)
+
+	// Valid input is not affected.
+	pj, err = ParseND([]byte(demo_ndjson), nil, WithSkipInvalidRecords(report))
+	if err != nil || len(skipped) != 0 {
+		t.Fatalf("valid input: got %v and %d skipped", err, len(skipped))
+	}
+	ref, err := ParseND([]byte(demo_ndjson), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pj.Tape, ref.Tape) {
+		t.Error("valid input: tape differs")
+	}
+
+	// Only invalid records.
+	pj, err = ParseND([]byte("tru\n[1,\n"), nil, WithSkipInvalidRecords(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := pj.Iter(); i.Advance() != TypeNone {
+		t.Error("no valid records: got a value")
+	}
+
+	// Comments may span records.
+	pj, err = ParseND([]byte("1 /* a\nb */ \n[x]\n3 // c"), nil, WithLenient(LenientComments), WithSkipInvalidRecords(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoots(t, pj, []interface{}{int64(1), int64(3)})
+	if len(skipped) != 1 || skipped[0].Line != 3 {
+		t.Errorf("comments: got skipped %v", skipped)
+	}
+}
+
+func TestSkipInvalidRecordsStream(t *testing.T) {
+	var sb strings.Builder
+	var want []interface{}
+	for i := 0; i < 1000; i++ {
+		if i%7 == 3 {
+			fmt.Fprintf(&sb, "{\"i\":%d,}\n", i)
+			continue
+		}
+		fmt.Fprintf(&sb, "{\"i\":%d}\n", i)
+		want = append(want, map[string]interface{}{"i": int64(i)})
+	}
+	input := sb.String()
+
+	var wantSkipped []*RecordError
+	_, err := ParseND([]byte(input), nil, WithSkipInvalidRecords(func(e *RecordError) {
+		wantSkipped = append(wantSkipped, e)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var skipped []*RecordError
+	res := make(chan Stream, 10)
+	ParseNDStreamWithOptions(context.Background(), strings.NewReader(input), res, nil,
+		WithStreamChunkSize(100),
+		WithStreamParserOptions(WithSkipInvalidRecords(func(e *RecordError) {
+			skipped = append(skipped, e)
+		})),
+	)
+	var got []interface{}
+	for r := range res {
+		if r.Error == io.EOF {
+			break
+		}
+		if r.Error != nil {
+			t.Fatal(r.Error)
+		}
+		i := r.Value.Iter()
+		if i.Advance() == TypeNone {
+			continue
+		}
+		i = r.Value.Iter()
+		v, err := i.Interface()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v.([]interface{})...)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %d records, want %d", len(got), len(want))
+	}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Fatalf("got skipped %v, want %v", skipped, wantSkipped)
+	}
+
+	// Array elements are not records.
+	res = make(chan Stream, 10)
+	ParseArrayStream(strings.NewReader(`[1,{"a":tru}]`), res, nil, WithSkipInvalidRecords(nil))
+	var perr *ParseError
+	if r := <-res; !errors.As(r.Error, &perr) {
+		t.Errorf("array stream: got %v, want parse error", r.Error)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// RootInfo describes where a root value was found in the input.
+type RootInfo struct {
+	// Index is the number of root values before it in the input.
+	// When streaming, the roots of previous results are counted.
+	Index int
+
+	// Offset and Length are the location of the value in the input in bytes.
+	// When streaming, Offset is from the start of the stream.
+	Offset, Length uint64
+
+	// Line is the line of the start of the value, starting at 1.
+	Line int
+}
+
+// rootLocation is the location of a root value in Message.
+type rootLocation struct {
+	start uint64
+	line  int // relative to the start of Message
+}
+
+// countRootLines counts the lines of the roots in input, which Message is a blanked copy of.
+func (pj *internalParsedJson) countRootLines(input []byte) {
+	line, offset := 1, uint64(0)
+	for i, loc := range pj.roots {
+		start := pj.rootBase.Offset + loc.start
+		line += bytes.Count(input[offset:start], []byte{'\n'})
+		offset = start
+		pj.roots[i].line = line
+	}
+	pj.rootBase.Line = 1
+}
+
+// RootInfo returns where root i of pj was found in the input, counting from 0.
+// Root locations are kept for values parsed from a byte slice and for streams,
+// except ParseArrayStream, as long as Message is unchanged.
+// They are not kept by ParseReader and deserialization.
+func (pj *ParsedJson) RootInfo(i int) (RootInfo, error) {
+	if len(pj.roots) == 0 || pj.Message == nil {
+		return RootInfo{}, errors.New("root locations are not available")
+	}
+	if i < 0 || i >= len(pj.roots) {
+		return RootInfo{}, fmt.Errorf("root %d out of range, %d roots", i, len(pj.roots))
+	}
+	loc := pj.roots[i]
+	end := uint64(len(pj.Message))
+	if i+1 < len(pj.roots) {
+		end = pj.roots[i+1].start
+	}
+	if loc.start > end || end > uint64(len(pj.Message)) {
+		return RootInfo{}, errors.New("root locations do not match message")
+	}
+	// Values are separated by whitespace, or blanked comments and separators.
+	value := bytes.TrimRight(pj.Message[loc.start:end], " \t\r\n")
+	return RootInfo{
+		Index:  pj.rootBase.Index + i,
+		Offset: pj.rootBase.Offset + loc.start,
+		Length: uint64(len(value)),
+		Line:   pj.rootBase.Line + loc.line - 1,
+	}, nil
+}
+
+// NumRoots returns the number of root values in pj.
+// For NDJSON this is the number of records.
+func (pj *ParsedJson) NumRoots() int {
+	return len(pj.rootOffsets())
+}
+
+// RootIter returns an iterator starting at root n, counting from 0.
+// The first call to Advance returns TypeRoot for root n, and the following roots can be read after it,
+// the same as from an iterator returned by Iter that has been advanced n times.
+func (pj *ParsedJson) RootIter(n int) (Iter, error) {
+	roots := pj.rootOffsets()
+	if n < 0 || n >= len(roots) {
+		return Iter{}, fmt.Errorf("root %d out of range, %d roots", n, len(roots))
+	}
+	return Iter{tape: *pj, off: int(roots[n])}, nil
+}
+
+// Slice returns a view of the roots from up to, but not including, to.
+// Nothing is copied, so the view is only valid as long as pj is,
+// and it must not be used as a destination or supplied for reuse.
+// Use Clone to get a copy that only contains the roots of the view.
+// Views of the same ParsedJson can be read concurrently.
+// RootInfo of the view returns the index of each root in the input, not in the view.
+func (pj *ParsedJson) Slice(from, to int) (*ParsedJson, error) {
+	roots := pj.rootOffsets()
+	if from < 0 || to < from || to > len(roots) {
+		return nil, fmt.Errorf("slice [%d:%d] out of range, %d roots", from, to, len(roots))
+	}
+	start, end := len(pj.Tape), len(pj.Tape)
+	if from < len(roots) {
+		start = int(roots[from])
+	}
+	if to < len(roots) {
+		end = int(roots[to])
+	}
+	view := &ParsedJson{
+		Message:   pj.Message,
+		Tape:      pj.Tape[:end:end],
+		Strings:   pj.Strings,
+		rootBase:  pj.rootBase,
+		rootTape:  roots[from:to:to],
+		tapeStart: start,
+	}
+	view.rootBase.Index += from
+	if len(pj.roots) == len(roots) {
+		view.roots = pj.roots[from:to:to]
+		if to < len(pj.roots) && pj.roots[to].start <= uint64(len(pj.Message)) {
+			// The last value ends before the next root.
+			view.Message = pj.Message[:pj.roots[to].start]
+		}
+	}
+	return view, nil
+}
+
+// rootOffsets returns the tape offsets of the roots.
+// They are recorded when parsing and deserializing, otherwise they are found on first use.
+func (pj *ParsedJson) rootOffsets() []uint64 {
+	if pj.rootTape == nil {
+		pj.rootTape = findRoots(make([]uint64, 0, 1), pj.Tape, pj.tapeStart)
+	}
+	return pj.rootTape
+}
+
+// findRoots appends the offsets of the roots in tape, starting at off, to dst.
+func findRoots(dst, tape []uint64, off int) []uint64 {
+	for off < len(tape) && Tag(tape[off]>>56) == TagRoot {
+		// The root points past its end.
+		next := int(tape[off] & JSONVALUEMASK)
+		if next <= off || next > len(tape) {
+			break
+		}
+		dst = append(dst, uint64(off))
+		off = next
+	}
+	return dst
+}
+
+// rebaseTape moves the offsets in tape and roots back by base,
+// when the tape of a Slice starting at base has been copied.
+func rebaseTape(tape, roots []uint64, base uint64) {
+	for i := range roots {
+		roots[i] -= base
+	}
+	for off := 0; off < len(tape); off++ {
+		switch Tag(tape[off] >> 56) {
+		case TagRoot, TagObjectStart, TagObjectEnd, TagArrayStart, TagArrayEnd:
+			// Offsets are within the slice, so the tag is unchanged.
+			tape[off] -= base
+		case TagInteger, TagUint, TagFloat, TagString, TagNumber, TagDecimal:
+			// Skip the value.
+			off++
+		}
+	}
+}
@@ -0,0 +1,367 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// rootTexts returns the input text of each root of pj, with its line and index.
+func rootTexts(t *testing.T, pj *ParsedJson, input string) []string {
+	t.Helper()
+	var got []string
+	for i := 0; ; i++ {
+		info, err := pj.RootInfo(i)
+		if err != nil {
+			break
+		}
+		got = append(got, fmt.Sprintf("%d:%d:%s", info.Index, info.Line, input[info.Offset:info.Offset+info.Length]))
+	}
+	return got
+}
+
+func TestRootInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(b []byte, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error)
+		input string
+		opts  []ParserOption
+		want  []string
+	}{
+		{
+			name:  "single",
+			parse: Parse,
+			input: "\n  {\"a\": [1, 2]}  \n",
+			want:  []string{`0:2:{"a": [1, 2]}`},
+		},
+		{
+			name:  "ndjson",
+			parse: ParseND,
+			input: "\n{\"a\":1}\n\n  [2]  \n\"x\"\r\n4",
+			want:  []string{`0:2:{"a":1}`, `1:4:[2]`, `2:5:"x"`, `3:6:4`},
+		},
+		{
+			name:  "bom",
+			parse: ParseND,
+			input: "\xef\xbb\xbf1\n2",
+			opts:  []ParserOption{WithLenient(LenientBOM)},
+			want:  []string{`0:1:1`, `1:2:2`},
+		},
+		{
+			name:  "comments",
+			parse: ParseND,
+			input: "1 /* a\n b */\n[2] // c\n3",
+			opts:  []ParserOption{WithLenient(LenientComments)},
+			want:  []string{`0:1:1`, `1:3:[2]`, `2:4:3`},
+		},
+		{
+			name:  "skipped",
+			parse: ParseND,
+			input: "1\n[2,]\n3\ntru\n\"5\"",
+			opts:  []ParserOption{WithSkipInvalidRecords(nil)},
+			want:  []string{`0:1:1`, `1:3:3`, `2:5:"5"`},
+		},
+		{
+			name:  "concatenated",
+			parse: ParseConcatenated,
+			input: "{\n  \"a\": 1\n}{\"b\":2}\n\n[\n3\n] \"x\"",
+			want:  []string{"0:1:{\n  \"a\": 1\n}", `1:3:{"b":2}`, "2:5:[\n3\n]", `3:7:"x"`},
+		},
+		{
+			name:  "sequence",
+			parse: ParseSequence,
+			input: "\x1e{\"a\":1}\n\x1e\x1e[2]\n\x1e3\n",
+			want:  []string{`0:1:{"a":1}`, `1:2:[2]`, `2:3:3`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pj, err := tt.parse([]byte(tt.input), nil, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := rootTexts(t, pj, tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := rootTexts(t, pj.Clone(nil), tt.input); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("clone: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	pj, err := ParseND([]byte("1\n2"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []int{-1, 2} {
+		if _, err := pj.RootInfo(i); err == nil {
+			t.Errorf("root %d: expected error", i)
+		}
+	}
+
+	// Reused parsers.
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const nd = "[1]\n\n{}"
+	for i := 0; i < 2; i++ {
+		pj, err = p.ParseND([]byte(nd), pj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rootTexts(t, pj, nd); strings.Join(got, "|") != "0:1:[1]|1:3:{}" {
+			t.Errorf("parser: got %q", got)
+		}
+	}
+	if _, err := p.ParseConcatenated([]byte("{}{}"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.ParseReader(strings.NewReader("{}{}"), nil); err == nil {
+		t.Error("ParseReader: expected error for concatenated values")
+	}
+	pj, err = p.ParseReader(strings.NewReader("{}"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pj.RootInfo(0); err == nil {
+		t.Error("ParseReader: expected root locations to be unavailable")
+	}
+}
+
+func TestRootInfoStream(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 500; i++ {
+		if i%10 == 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "{\"i\":%d}\n", i)
+	}
+	input := sb.String()
+	ref, err := ParseND([]byte(input), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := rootTexts(t, ref, input)
+
+	streams := map[string]func(r io.Reader, res chan<- Stream){
+		"ndjson": func(r io.Reader, res chan<- Stream) {
+			ParseNDStreamWithOptions(context.Background(), r, res, nil, WithStreamChunkSize(100))
+		},
+		"concatenated": func(r io.Reader, res chan<- Stream) {
+			ParseConcatenatedStream(r, res, nil)
+		},
+	}
+	for name, stream := range streams {
+		t.Run(name, func(t *testing.T) {
+			res := make(chan Stream, 10)
+			stream(strings.NewReader(input), res)
+			var got []string
+			for r := range res {
+				if r.Error == io.EOF {
+					break
+				}
+				if r.Error != nil {
+					t.Fatal(r.Error)
+				}
+				got = append(got, rootTexts(t, r.Value, input)...)
+			}
+			if strings.Join(got, "|") != strings.Join(want, "|") {
+				t.Errorf("got %d roots, want %d: %q", len(got), len(want), got)
+			}
+		})
+	}
+}
+
+// rootJSON returns the JSON of each root from i to the end of the tape.
+func rootJSON(t *testing.T, i *Iter) []string {
+	t.Helper()
+	var got []string
+	var elem Iter
+	for {
+		typ, err := i.AdvanceIter(&elem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != TypeRoot {
+			return got
+		}
+		b, err := elem.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(b))
+	}
+}
+
+func TestRootIter(t *testing.T) {
+	const input = "{\"a\":1}\n[2,{\"b\":\"x\"}]\n\"three\"\n4.5\nnull"
+	want := []string{`{"a":1}`, `[2,{"b":"x"}]`, `"three"`, `4.5`, `null`}
+	pj, err := ParseND([]byte(input), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ser := NewSerializer()
+	deser, err := ser.Deserialize(ser.Serialize(nil, *pj), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, pj := range map[string]*ParsedJson{
+		"parsed":       pj,
+		"deserialized": deser,
+		"tape":         {Message: pj.Message, Tape: pj.Tape, Strings: pj.Strings},
+	} {
+		if got := pj.NumRoots(); got != len(want) {
+			t.Fatalf("%s: got %d roots, want %d", name, got, len(want))
+		}
+		for n := range want {
+			i, err := pj.RootIter(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// All following roots can be read.
+			if got := rootJSON(t, &i); strings.Join(got, "|") != strings.Join(want[n:], "|") {
+				t.Errorf("%s: root %d: got %q, want %q", name, n, got, want[n:])
+			}
+		}
+		for _, n := range []int{-1, len(want)} {
+			if _, err := pj.RootIter(n); err == nil {
+				t.Errorf("%s: root %d: expected error", name, n)
+			}
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	const input = "{\"a\":1}\n[2,{\"b\":\"x\"}]\n\"three\"\n4.5\nnull"
+	want := []string{`{"a":1}`, `[2,{"b":"x"}]`, `"three"`, `4.5`, `null`}
+	pj, err := ParseND([]byte(input), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tape := append([]uint64(nil), pj.Tape...)
+	ser := NewSerializer()
+	for from := 0; from <= len(want); from++ {
+		for to := from; to <= len(want); to++ {
+			view, err := pj.Slice(from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantJSON := strings.Join(want[from:to], "\n")
+			check := func(name string, view *ParsedJson) {
+				t.Helper()
+				if got := view.NumRoots(); got != to-from {
+					t.Errorf("[%d:%d] %s: got %d roots", from, to, name, got)
+				}
+				i := view.Iter()
+				if got := rootJSON(t, &i); strings.Join(got, "\n") != wantJSON {
+					t.Errorf("[%d:%d] %s: got %q, want %q", from, to, name, got, wantJSON)
+				}
+			}
+			check("view", view)
+			check("clone", view.Clone(nil))
+			deser, err := ser.Deserialize(ser.Serialize(nil, *view), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			check("deserialized", deser)
+			if to > from {
+				// Root info refers to the input.
+				texts := rootTexts(t, view, input)
+				if len(texts) != to-from || texts[0] != fmt.Sprintf("%d:%d:%s", from, from+1, want[from]) {
+					t.Errorf("[%d:%d]: got root info %q", from, to, texts)
+				}
+				last, err := view.RootIter(to - from - 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if typ := last.Advance(); typ != TypeRoot {
+					t.Errorf("[%d:%d]: got %v for the last root", from, to, typ)
+				}
+				if typ := last.Advance(); typ != TypeNone {
+					t.Errorf("[%d:%d]: got %v after the last root", from, to, typ)
+				}
+			}
+			// Views of views.
+			if to > from {
+				sub, err := view.Slice(1, to-from)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := sub.NumRoots(); got != to-from-1 {
+					t.Errorf("[%d:%d][1:]: got %d roots", from, to, got)
+				}
+				if info, err := sub.RootInfo(0); err == nil && info.Index != from+1 {
+					t.Errorf("[%d:%d][1:]: got index %d", from, to, info.Index)
+				}
+			}
+		}
+	}
+	if fmt.Sprint(tape) != fmt.Sprint(pj.Tape) {
+		t.Error("slicing modified the tape")
+	}
+	for _, r := range [][2]int{{-1, 1}, {2, 1}, {0, len(want) + 1}} {
+		if _, err := pj.Slice(r[0], r[1]); err == nil {
+			t.Errorf("[%d:%d]: expected error", r[0], r[1])
+		}
+	}
+}
+
+func TestSliceConcurrent(t *testing.T) {
+	const records = 1000
+	var sb strings.Builder
+	for i := 0; i < records; i++ {
+		fmt.Fprintf(&sb, "{\"id\":%d,\"name\":\"record %d\"}\n", i, i)
+	}
+	pj, err := ParseND([]byte(sb.String()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const workers = 4
+	sums := make(chan int64, workers)
+	per := pj.NumRoots() / workers
+	for w := 0; w < workers; w++ {
+		view, err := pj.Slice(w*per, (w+1)*per)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			var sum int64
+			var elem Element
+			view.ForEach(func(i Iter) error {
+				if _, err := i.FindElement(&elem, "id"); err == nil {
+					v, _ := elem.Iter.Int()
+					sum += v
+				}
+				return nil
+			})
+			sums <- sum
+		}()
+	}
+	var total int64
+	for w := 0; w < workers; w++ {
+		total += <-sums
+	}
+	if want := int64(records * (records - 1) / 2); total != want {
+		t.Errorf("got sum %d, want %d", total, want)
+	}
+}
@@ -0,0 +1,628 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+func newInternalParsedJson(reuse *ParsedJson, opts []ParserOption) (*internalParsedJson, error) {
+	var pj *internalParsedJson
+	if reuse != nil && reuse.internal != nil {
+		pj = reuse.internal
+		pj.ParsedJson = *reuse
+		pj.ParsedJson.internal = nil
+		reuse = &ParsedJson{}
+	}
+	if pj == nil {
+		pj = &internalParsedJson{}
+	}
+	if err := pj.applyOptions(opts); err != nil {
+		return nil, err
+	}
+	return pj, nil
+}
+
+// Parse a block of data and return the parsed JSON.
+// The document may be an object, an array or a single scalar value.
+// An optional block of previously parsed json can be supplied to reduce allocations.
+// Invalid JSON is reported as a *ParseError.
+func Parse(b []byte, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error) {
+	pj, err := newInternalParsedJson(reuse, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = pj.parseMessage(b, false)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &pj.ParsedJson
+	parsed.internal = pj
+	return parsed, nil
+}
+
+// ParseND will parse newline delimited JSON.
+// Each line may contain an object, an array or a single scalar value.
+// An optional block of previously parsed json can be supplied to reduce allocations.
+// Invalid JSON is reported as a *ParseError.
+func ParseND(b []byte, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error) {
+	pj, err := newInternalParsedJson(reuse, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = pj.parseMessage(b, true)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &pj.ParsedJson
+	parsed.internal = pj
+	return parsed, nil
+}
+
+// ParseConcatenated will parse concatenated JSON values.
+// Values may be separated by any whitespace, or follow each other directly.
+// Numbers, true, false and null must be followed by whitespace
+// unless they are the last value.
+// Each value is contained within a root tag, as with ParseND.
+// An optional block of previously parsed json can be supplied to reduce allocations.
+// Invalid JSON is reported as a *ParseError.
+func ParseConcatenated(b []byte, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error) {
+	return parseSeparated(b, reuse, opts, separatorWhitespace)
+}
+
+// ParseSequence will parse a JSON text sequence as defined by RFC 7464.
+// Values are separated by RS (0x1e) characters and/or whitespace.
+// Each value is contained within a root tag, as with ParseND.
+// An optional block of previously parsed json can be supplied to reduce allocations.
+// Invalid JSON is reported as a *ParseError.
+func ParseSequence(b []byte, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error) {
+	return parseSeparated(b, reuse, opts, separatorRecord)
+}
+
+func parseSeparated(b []byte, reuse *ParsedJson, opts []ParserOption, sep rootSeparator) (*ParsedJson, error) {
+	pj, err := newInternalParsedJson(reuse, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = pj.parseSeparated(b, sep)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &pj.ParsedJson
+	parsed.internal = pj
+	return parsed, nil
+}
+
+// A Stream is used to stream back results.
+// Either Error or Value will be set on returned results.
+type Stream struct {
+	Value *ParsedJson
+	Error error
+
+	// skipped records to report before the result is returned.
+	skipped []*RecordError
+}
+
+// ParseNDStream will parse a stream and return parsed JSON to the supplied result channel.
+// The method will return immediately.
+// Each element is contained within a root tag.
+//   <root>Element 1</root><root>Element 2</root>...
+// Each result will contain an unspecified number of full elements,
+// so it can be assumed that each result starts and ends with a root tag.
+// The parser will keep parsing until writes to the result stream blocks.
+// A stream is finished when a non-nil Error is returned.
+// If the stream was parsed until the end the Error value will be io.EOF
+// The channel will be closed after an error has been returned.
+// An optional channel for returning consumed results can be provided.
+// There is no guarantee that elements will be consumed, so always use
+// non-blocking writes to the reuse channel.
+// Parser options are applied to each parsed block.
+func ParseNDStream(r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...ParserOption) {
+	ParseNDStreamContext(context.Background(), r, res, reuse, opts...)
+}
+
+// ParseNDStreamContext will parse a stream like ParseNDStream until ctx is cancelled.
+// When ctx is cancelled, reading and parsing stops, unsent results are dropped,
+// and a final Stream with ctx.Err() as Error is sent before res is closed,
+// unless an error has already been returned.
+// A read from r that is in progress is not interrupted,
+// but its result is discarded when it returns.
+// res is closed when all goroutines of the stream have exited,
+// so keep reading from res until it is closed.
+func ParseNDStreamContext(ctx context.Context, r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...ParserOption) {
+	ParseNDStreamWithOptions(ctx, r, res, reuse, WithStreamParserOptions(opts...))
+}
+
+// ParseNDStreamWithOptions will parse a stream like ParseNDStreamContext,
+// with the block size, concurrency, parser options, record length limit, decompression and following set by opts.
+// Invalid options are returned as the only result.
+func ParseNDStreamWithOptions(ctx context.Context, r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...StreamOption) {
+	so, err := newStreamOptions(opts)
+	if err != nil {
+		streamError(res, err)
+		return
+	}
+	parseNDStream(ctx, r, nil, res, reuse, so)
+}
+
+// ParseNDFile will parse the NDJSON file name like ParseNDStreamWithOptions.
+// Compressed files are decompressed as described for WithStreamDecompression,
+// unless the file is followed with WithStreamFollow.
+// The file is closed when reading stops.
+// Invalid options and errors opening the file are returned as the only result.
+func ParseNDFile(ctx context.Context, name string, res chan<- Stream, reuse <-chan *ParsedJson, opts ...StreamOption) {
+	so, err := newStreamOptions(opts)
+	if err != nil {
+		streamError(res, err)
+		return
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		streamError(res, err)
+		return
+	}
+	so.decompress = so.follow == 0
+	parseNDStream(ctx, f, f, res, reuse, so)
+}
+
+// parseNDStream parses the NDJSON stream r with the settings so.
+// closer is closed when reading stops, if not nil.
+func parseNDStream(ctx context.Context, r io.Reader, closer io.Closer, res chan<- Stream, reuse <-chan *ParsedJson, so streamOptions) {
+	so.records = true
+	so.input = closer
+	if so.decompress {
+		d := newDecompressor(r, closer)
+		r, so.input = d, d
+	}
+	if so.follow > 0 {
+		f := newFollower(ctx, r, closer, so)
+		r, so.input = f, f
+	}
+	buf := bufio.NewReaderSize(r, so.chunkSize)
+	var offset int // offset in the stream of the next block
+	parseStream(ctx, res, reuse, so, separatorNewline, func(tmp []byte) ([]byte, error) {
+		tmp = tmp[:so.chunkSize]
+		n, err := buf.Read(tmp)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		tmp = tmp[:n]
+		// Read until Newline
+		for err == nil {
+			if so.follow > 0 && len(tmp) > 0 && tmp[len(tmp)-1] == '\n' {
+				// Don't wait for the next line.
+				break
+			}
+			if so.maxRecordLength > 0 && len(tmp)-bytes.LastIndexByte(tmp, '\n')-1 > so.maxRecordLength {
+				// Don't read the rest of the record.
+				break
+			}
+			var b []byte
+			b, err = buf.ReadSlice('\n')
+			tmp = append(tmp, b...)
+			if err == bufio.ErrBufferFull {
+				err = nil
+				continue
+			}
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		if so.maxRecordLength > 0 {
+			if start := longRecord(tmp, so.maxRecordLength); start >= 0 {
+				return nil, fmt.Errorf("record at offset %d: %w", offset+start, ErrRecordTooLong)
+			}
+		}
+		offset += len(tmp)
+		return tmp, err
+	})
+}
+
+// longRecord returns the offset of the first line in b longer than max bytes,
+// not counting the newline, or -1 if there is none.
+func longRecord(b []byte, max int) int {
+	for start := 0; start < len(b); {
+		n := bytes.IndexByte(b[start:], '\n')
+		if n < 0 {
+			n = len(b) - start
+		}
+		if n > max {
+			return start
+		}
+		start += n + 1
+	}
+	return -1
+}
+
+// ParseConcatenatedStream will parse a stream of concatenated JSON values
+// and return parsed JSON to the supplied result channel.
+// Values are separated as described for ParseConcatenated,
+// and results are returned as described for ParseNDStream.
+// The stream is split into blocks between values, so comments
+// allowed by LenientComments must not contain quotes or brackets.
+func ParseConcatenatedStream(r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...ParserOption) {
+	ParseConcatenatedStreamWithOptions(context.Background(), r, res, reuse, WithStreamParserOptions(opts...))
+}
+
+// ParseConcatenatedStreamWithOptions will parse a stream like ParseConcatenatedStream
+// until ctx is cancelled, as described for ParseNDStreamContext.
+// The block size, concurrency and parser options are set by opts.
+// Invalid options, including the options that only apply to NDJSON, are returned as the only result.
+func ParseConcatenatedStreamWithOptions(ctx context.Context, r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...StreamOption) {
+	so, err := newValueStreamOptions(opts)
+	if err != nil {
+		streamError(res, err)
+		return
+	}
+	var rest []byte
+	var scan rootScanner
+	parseStream(ctx, res, reuse, so, separatorWhitespace, func(tmp []byte) ([]byte, error) {
+		tmp = append(tmp, rest...)
+		scanned := len(tmp)
+		cut := -1
+		var err error
+		// Read until the block is full and a value has ended.
+		for err == nil && (cut < 0 || len(tmp) < so.chunkSize || len(bytes.TrimSpace(tmp[:cut])) == 0) {
+			if len(tmp) == cap(tmp) {
+				tmp = append(tmp[:cap(tmp)], make([]byte, so.chunkSize)...)[:len(tmp)]
+			}
+			var n int
+			n, err = r.Read(tmp[len(tmp):cap(tmp)])
+			tmp = tmp[:len(tmp)+n]
+			if end := scan.scan(tmp[scanned:]); end >= 0 {
+				cut = scanned + end
+			}
+			scanned = len(tmp)
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF {
+			cut = len(tmp)
+		}
+		// Keep the start of the next value for the next block.
+		rest = append(rest[:0], tmp[cut:]...)
+		tmp = tmp[:cut]
+		if len(bytes.TrimSpace(tmp)) == 0 {
+			// Only whitespace was left at the end.
+			tmp = tmp[:0]
+		}
+		return tmp, err
+	})
+}
+
+// ParseSequenceStream will parse a JSON text sequence as defined by RFC 7464
+// and return parsed JSON to the supplied result channel.
+// Values are separated as described for ParseSequence,
+// and results are returned as described for ParseNDStream.
+// The stream is split into blocks at RS characters.
+func ParseSequenceStream(r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...ParserOption) {
+	ParseSequenceStreamWithOptions(context.Background(), r, res, reuse, WithStreamParserOptions(opts...))
+}
+
+// ParseSequenceStreamWithOptions will parse a stream like ParseSequenceStream
+// until ctx is cancelled, as described for ParseNDStreamContext.
+// The block size, concurrency and parser options are set by opts.
+// Invalid options, including the options that only apply to NDJSON, are returned as the only result.
+func ParseSequenceStreamWithOptions(ctx context.Context, r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson, opts ...StreamOption) {
+	so, err := newValueStreamOptions(opts)
+	if err != nil {
+		streamError(res, err)
+		return
+	}
+	buf := bufio.NewReaderSize(r, so.chunkSize)
+	parseStream(ctx, res, reuse, so, separatorRecord, func(tmp []byte) ([]byte, error) {
+		tmp = tmp[:so.chunkSize]
+		n, err := buf.Read(tmp)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		tmp = tmp[:n]
+		// Read until the start of the next value,
+		// until the block contains at least one value.
+		for err != io.EOF {
+			b, err2 := buf.ReadBytes(recordSeparator)
+			if err2 != nil && err2 != io.EOF {
+				return nil, err2
+			}
+			tmp = append(tmp, b...)
+			// Forward io.EOF
+			err = err2
+			if len(bytes.Trim(tmp, sequenceSpace)) > 0 {
+				break
+			}
+		}
+		if len(bytes.Trim(tmp, sequenceSpace)) == 0 {
+			// Only separators were left.
+			tmp = tmp[:0]
+		}
+		return tmp, err
+	})
+}
+
+// sequenceSpace contains the characters allowed between values of a JSON text sequence.
+const sequenceSpace = " \t\r\n\x1e"
+
+// rootScanner finds the ends of concatenated root values in a stream.
+type rootScanner struct {
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+// scan continues scanning with b and returns the offset in b
+// after the last root value that ended, or -1 if none did.
+// Invalid input is only scanned far enough to keep blocks bounded,
+// the error is reported by the parser.
+func (s *rootScanner) scan(b []byte) int {
+	end := -1
+	for i, c := range b {
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case c == '\\':
+				s.escaped = true
+			case c == '"':
+				s.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			s.inString = true
+		case '{', '[':
+			s.depth++
+		case '}', ']':
+			s.depth--
+			if s.depth <= 0 {
+				s.depth = 0
+				end = i + 1
+			}
+		case ' ', '\t', '\r', '\n':
+			if s.depth == 0 {
+				end = i + 1
+			}
+		}
+	}
+	return end
+}
+
+// streamChunkSize is the approximate size of the blocks parsed when streaming.
+const streamChunkSize = 10 << 20
+
+// parseStream parses blocks of JSON values separated by sep concurrently
+// and returns the results in order to res.
+// read is called with an empty buffer to fill with the next block.
+// It should return io.EOF with the last block.
+// Parsing stops when ctx is cancelled.
+func parseStream(ctx context.Context, res chan<- Stream, reuse <-chan *ParsedJson, so streamOptions, sep rootSeparator, read func(tmp []byte) ([]byte, error)) {
+	// Check options before starting.
+	opts := so.parserOpts
+	check, err := newInternalParsedJson(nil, opts)
+	if err != nil {
+		if so.input != nil {
+			so.input.Close()
+		}
+		streamError(res, err)
+		return
+	}
+	// Invalid records are collected by each block and reported in order.
+	skip := check.skipInvalid && so.records
+	report := check.skipReport
+	bufSize := so.chunkSize + 1024
+	tmpPool := sync.Pool{New: func() interface{} {
+		return make([]byte, bufSize)
+	}}
+	queue := make(chan chan Stream, so.workers)
+	// The reader and the blocks being parsed.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		// Forward finished items in order.
+		// res is closed when all other goroutines have exited.
+		defer func() {
+			wg.Wait()
+			close(res)
+		}()
+		end := false
+		roots := 0 // returned so far
+		cancelled := func() {
+			if !end {
+				res <- Stream{Error: ctx.Err()}
+			}
+		}
+		for {
+			var items chan Stream
+			var ok bool
+			select {
+			case items, ok = <-queue:
+			case <-ctx.Done():
+				cancelled()
+				return
+			}
+			if !ok {
+				if ctx.Err() != nil {
+					// Reading stopped because of cancellation.
+					cancelled()
+				}
+				return
+			}
+			var i Stream
+			select {
+			case i = <-items:
+			case <-ctx.Done():
+				cancelled()
+				return
+			}
+			if i.Value != nil {
+				i.Value.rootBase.Index = roots
+				roots += len(i.Value.roots)
+			}
+			if report != nil {
+				for _, e := range i.skipped {
+					report(e)
+				}
+			}
+			i.skipped = nil
+			select {
+			case res <- i:
+			default:
+				if !end {
+					// Block if we haven't returned an error
+					select {
+					case res <- i:
+					case <-ctx.Done():
+						cancelled()
+						return
+					}
+				}
+			}
+			if i.Error != nil {
+				end = true
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		defer close(queue)
+		if so.input != nil {
+			defer so.input.Close()
+		}
+		// Location of the next block in the stream.
+		offset, line := uint64(0), 1
+		for ctx.Err() == nil {
+			tmp := tmpPool.Get().([]byte)
+			tmp, err := read(tmp[:0])
+			blockOffset, blockLine := offset, line
+			var locate func(*ParseError) *ParseError
+			if so.locate != nil {
+				locate = so.locate()
+			}
+			if so.records {
+				offset += uint64(len(tmp))
+				line += bytes.Count(tmp, []byte{'\n'})
+			}
+			if ctx.Err() != nil {
+				if tmp != nil {
+					tmpPool.Put(tmp[:0])
+				}
+				return
+			}
+			if err != nil && err != io.EOF {
+				queueError(ctx, queue, err)
+				return
+			}
+
+			if len(tmp) > 0 {
+				// Buffered, so the result can be dropped if cancelled.
+				result := make(chan Stream, 1)
+				select {
+				case queue <- result:
+				case <-ctx.Done():
+					tmpPool.Put(tmp[:0])
+					return
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					var pj internalParsedJson
+					select {
+					case v := <-reuse:
+						if cap(v.Message) >= bufSize {
+							tmpPool.Put(v.Message)
+							v.Message = nil
+						}
+						pj.ParsedJson = *v
+
+					default:
+					}
+					// Options have been checked above.
+					_ = pj.applyOptions(opts)
+					var skipped []*RecordError
+					pj.skipInvalid = skip
+					pj.skipReport = func(e *RecordError) {
+						e.Line += blockLine - 1
+						e.Start += blockOffset
+						e.End += blockOffset
+						// Blocks start at the start of a line.
+						e.Err.Line += blockLine - 1
+						e.Err.Offset += blockOffset
+						skipped = append(skipped, e)
+					}
+					parseErr := pj.parseSeparated(tmp, sep)
+					if perr, ok := parseErr.(*ParseError); ok && locate != nil {
+						parseErr = locate(perr)
+					}
+					if parseErr != nil {
+						result <- Stream{
+							Value: nil,
+							Error: fmt.Errorf("parsing input: %w", parseErr),
+						}
+						return
+					}
+					parsed := pj.ParsedJson
+					if so.records {
+						parsed.rootBase.Offset += blockOffset
+						parsed.rootBase.Line += blockLine - 1
+					} else {
+						parsed.roots = parsed.roots[:0]
+					}
+					result <- Stream{
+						Value:   &parsed,
+						Error:   nil,
+						skipped: skipped,
+					}
+				}()
+			} else {
+				tmpPool.Put(tmp)
+			}
+			if err != nil {
+				// Should only really be io.EOF
+				queueError(ctx, queue, err)
+				return
+			}
+		}
+	}()
+}
+
+// streamError returns err as the only result of a stream.
+func streamError(res chan<- Stream, err error) {
+	go func() {
+		res <- Stream{Error: err}
+		close(res)
+	}()
+}
+
+func queueError(ctx context.Context, queue chan chan Stream, err error) {
+	result := make(chan Stream, 1)
+	result <- Stream{
+		Value: nil,
+		Error: err,
+	}
+	select {
+	case queue <- result:
+	case <-ctx.Done():
+	}
+}
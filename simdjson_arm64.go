@@ -0,0 +1,28 @@
+//go:build !appengine && !noasm && gc
+// +build !appengine,!noasm,gc
+
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+// SupportedCPU will return whether the CPU is supported by the SIMD accelerated parser.
+// Parsing is possible on all CPUs, but when false is returned
+// a slower pure Go fallback is used.
+func SupportedCPU() bool {
+	// Advanced SIMD (NEON) is mandatory on arm64.
+	return true
+}
@@ -1,5 +1,5 @@
-//go:build !amd64 || appengine || !gc || noasm
-// +build !amd64 appengine !gc noasm
+//go:build (!amd64 && !arm64) || appengine || !gc || noasm
+// +build !amd64,!arm64 appengine !gc noasm
 
 /*
  * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
@@ -19,56 +19,9 @@
 
 package simdjson
 
-import (
-	"errors"
-	"fmt"
-	"io"
-)
-
-// SupportedCPU will return whether the CPU is supported.
+// SupportedCPU will return whether the CPU is supported by the SIMD accelerated parser.
+// Parsing is possible on all CPUs, but when false is returned
+// a slower pure Go fallback is used.
 func SupportedCPU() bool {
 	return false
 }
-
-// Parse a block of data and return the parsed JSON.
-// An optional block of previously parsed json can be supplied to reduce allocations.
-func Parse(b []byte, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error) {
-	return nil, errors.New("Unsupported platform")
-}
-
-// ParseND will parse newline delimited JSON.
-// An optional block of previously parsed json can be supplied to reduce allocations.
-func ParseND(b []byte, reuse *ParsedJson, opts ...ParserOption) (*ParsedJson, error) {
-	return nil, errors.New("Unsupported platform")
-}
-
-// A Stream is used to stream back results.
-// Either Error or Value will be set on returned results.
-type Stream struct {
-	Value *ParsedJson
-	Error error
-}
-
-// ParseNDStream will parse a stream and return parsed JSON to the supplied result channel.
-// The method will return immediately.
-// Each element is contained within a root tag.
-//   <root>Element 1</root><root>Element 2</root>...
-// Each result will contain an unspecified number of full elements,
-// so it can be assumed that each result starts and ends with a root tag.
-// The parser will keep parsing until writes to the result stream blocks.
-// A stream is finished when a non-nil Error is returned.
-// If the stream was parsed until the end the Error value will be io.EOF
-// The channel will be closed after an error has been returned.
-// An optional channel for returning consumed results can be provided.
-// There is no guarantee that elements will be consumed, so always use
-// non-blocking writes to the reuse channel.
-func ParseNDStream(r io.Reader, res chan<- Stream, reuse <-chan *ParsedJson) {
-	go func() {
-		res <- Stream{
-			Value: nil,
-			Error: fmt.Errorf("Unsupported platform"),
-		}
-		close(res)
-	}()
-	return
-}
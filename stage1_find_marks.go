@@ -0,0 +1,198 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+var jsonMarkupTable = [256]bool{
+	'{': true,
+	'}': true,
+	'[': true,
+	']': true,
+	',': true,
+	':': true,
+}
+
+func jsonMarkup(b byte) bool {
+	return jsonMarkupTable[b]
+}
+
+// jsonScalarStartTable contains the characters that can start a string, number or atom.
+var jsonScalarStartTable = [256]bool{
+	'"': true,
+	'-': true,
+	'0': true, '1': true, '2': true, '3': true, '4': true,
+	'5': true, '6': true, '7': true, '8': true, '9': true,
+	't': true,
+	'f': true,
+	'n': true,
+}
+
+func jsonScalarStart(b byte) bool {
+	return jsonScalarStartTable[b]
+}
+
+// stage1State is the state of stage 1 that is carried between blocks.
+// Keeping it outside findStructuralIndicesIn allows a message
+// to be processed in several parts, see ParseReader.
+type stage1State struct {
+	// does the last iteration end with an odd-length sequence of backslashes?
+	// either 0 or 1, but a 64-bit value
+	prev_iter_ends_odd_backslash uint64
+
+	// does the previous iteration end inside a double-quote pair?
+	prev_iter_inside_quote uint64 // either all zeros or all ones
+
+	// does the previous iteration end on something that is a predecessor of a
+	// pseudo-structural character - i.e. whitespace or a structural character
+	// effectively the very first char is considered to follow "whitespace" for the
+	// purposes of pseudo-structural character detection so we initialize to 1
+	prev_iter_ends_pseudo_pred uint64
+
+	error_mask uint64 // for unescaped characters within strings (ASCII code points < 0x20)
+
+	indexTotal int
+
+	// empty bits that are carried over to the next call to flatten_bits_incremental
+	carried uint64
+
+	// position of the last structural character relative to offset
+	position       uint64
+	stripped_index uint64
+
+	// offset into the message where processing continues
+	offset int
+
+	// UTF-8 is validated up to this offset in the message
+	utf8Validated int
+
+	// window is sent with the next index buffer.
+	window *window
+}
+
+func newStage1State() stage1State {
+	return stage1State{
+		prev_iter_ends_pseudo_pred: 1,
+		position:                   ^uint64(0),
+		stripped_index:             ^uint64(0),
+	}
+}
+
+func (pj *internalParsedJson) findStructuralIndices() bool {
+	buf := pj.Message
+	s := newStage1State()
+	pj.errStage1 = nil
+	pj.findStructuralIndicesIn(&s, buf, true)
+	if !pj.onDemand {
+		pj.indexChans <- indexChan{index: -1}
+	}
+
+	// a valid JSON file cannot have zero structural indexes - we should have found something
+	if s.error_mask != 0 || s.indexTotal == 0 {
+		return false
+	}
+
+	// break out if either
+	// - is there an unmatched quote at the end
+	// - the ending structural char is not either a '}' (normal json), a ']' (array style)
+	//   or the start of a scalar value
+	position := uint64(s.offset) + s.position
+	if s.prev_iter_inside_quote != 0 ||
+		position >= uint64(len(buf)) ||
+		!(buf[position] == '}' || buf[position] == ']' || pj.scalarStart(buf[position])) {
+		return false
+	}
+	return true
+}
+
+// findStructuralIndicesIn finds the structural indices of msg from s.offset
+// and sends them to stage 2.
+// When parsing on demand the indices are collected in pj.structurals instead.
+// Unless final is set only whole 64 byte blocks are processed,
+// and the rest of msg is left for the next call.
+func (pj *internalParsedJson) findStructuralIndicesIn(s *stage1State, msg []byte, final bool) {
+	buf := msg[s.offset:]
+	for len(buf) >= 64 || final && (len(buf) > 0 || s.stripped_index != ^uint64(0)) {
+
+		// The buffer is only used if indexes are sent.
+		index := indexChan{}
+		index.indexes = &pj.buffers[(pj.buffersOffset+1)%indexSlots]
+
+		// In case last index during previous round was stripped back, put it back
+		if s.stripped_index != ^uint64(0) {
+			s.position += s.stripped_index
+			index.indexes[0] = uint32(s.stripped_index)
+			index.length = 1
+			s.stripped_index = ^uint64(0)
+		}
+
+		processed := findStructuralBits(buf[:len(buf) & ^63], &s.prev_iter_ends_odd_backslash,
+			&s.prev_iter_inside_quote, &s.error_mask,
+			&s.prev_iter_ends_pseudo_pred,
+			index.indexes, &index.length, &s.carried, &s.position, pj.ndjson)
+
+		// Check if we have at most a single iteration of 64 bytes left, tag on to previous invocation
+		if final && uint64(len(buf))-processed <= 64 {
+			// Process last 64 bytes in larger buffer (to safeguard against reading beyond the end of the buffer)
+			paddedBuf := [128]byte{}
+			copy(paddedBuf[:], buf[processed:])
+			paddedBytes := uint64(len(buf)) - processed
+			processed += findStructuralBits(paddedBuf[:paddedBytes], &s.prev_iter_ends_odd_backslash,
+				&s.prev_iter_inside_quote, &s.error_mask,
+				&s.prev_iter_ends_pseudo_pred,
+				index.indexes, &index.length, &s.carried, &s.position, pj.ndjson)
+		}
+
+		if pj.utf8Mode == UTF8Reject {
+			end := s.offset + int(processed)
+			n, bad := validateUTF8(msg[s.utf8Validated:end], final && end == len(msg), pj.simd)
+			if bad >= 0 {
+				pj.errStage1 = newParseError(msg, uint64(s.utf8Validated+bad), 1, ErrorKindInvalidUTF8)
+				s.error_mask = ^uint64(0)
+				return
+			}
+			s.utf8Validated += n
+		}
+
+		if !(final && uint64(len(buf)) == processed) && index.length > 0 && !jsonMarkup(msg[uint64(s.offset)+s.position]) {
+			// There may be a dangling quote at the end of the index buffer
+			// Strip it from current index buffer and save for next round
+			s.stripped_index = uint64(index.indexes[index.length-1])
+			s.position -= s.stripped_index
+			index.length -= 1
+		}
+
+		if final && uint64(len(buf)) == processed && s.prev_iter_inside_quote != 0 && index.length > 0 {
+			// The message ends inside a string that starts at the last index.
+			// Drop it, so stage 2 doesn't read the string beyond the end of the message.
+			index.length -= 1
+		}
+
+		if index.length > 0 && pj.onDemand {
+			pj.appendStructurals(index)
+			s.indexTotal += index.length
+		} else if index.length > 0 {
+			pj.buffersOffset++
+			index.window = s.window
+			s.window = nil
+			pj.indexChans <- index
+			s.indexTotal += index.length
+		}
+
+		buf = buf[processed:]
+		s.offset += int(processed)
+		s.position -= processed
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !appengine && !noasm && gc
+// +build !appengine,!noasm,gc
+
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+// findStructuralBits calls the fastest stage 1 implementation supported by the CPU.
+func findStructuralBits(buf []byte, prev_iter_ends_odd_backslash *uint64,
+	prev_iter_inside_quote, error_mask *uint64,
+	prev_iter_ends_pseudo_pred *uint64,
+	indexes *[indexSize]uint32, index *int, carried *uint64, position *uint64,
+	ndjson uint64) (processed uint64) {
+	return find_structural_bits_in_slice_neon(buf, prev_iter_ends_odd_backslash,
+		prev_iter_inside_quote, error_mask,
+		prev_iter_ends_pseudo_pred,
+		indexes, index, carried, position, ndjson)
+}
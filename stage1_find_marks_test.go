@@ -0,0 +1,116 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFindStructuralIndices(t *testing.T) {
+	parsed := []string{
+		`{"Image":{"Width":800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		` "Image":{"Width":800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`        :{"Width":800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`         {"Width":800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`          "Width":800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                 :800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                  800,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                     ,"Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                      "Height":600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                              :600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                               600,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                  ,"Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                   "Title":"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                          :"View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                           "View from 15th Floor","Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                 ,"Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                  "Thumbnail":{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                             :{"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                              {"Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                               "Url":"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                    :"http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                     "http://www.example.com/image/481989943","Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                             ,"Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                              "Height":125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                      :125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                       125,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                          ,"Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                           "Width":100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                  :100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                   100},"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                      },"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                       ,"Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                        "Animated":false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                                  :false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                                   false,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                                        ,"IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                                         "IDs":[116,943,234,38793]}}`,
+		`                                                                                                                                                                              :[116,943,234,38793]}}`,
+		`                                                                                                                                                                               [116,943,234,38793]}}`,
+		`                                                                                                                                                                                116,943,234,38793]}}`,
+		`                                                                                                                                                                                   ,943,234,38793]}}`,
+		`                                                                                                                                                                                    943,234,38793]}}`,
+		`                                                                                                                                                                                       ,234,38793]}}`,
+		`                                                                                                                                                                                        234,38793]}}`,
+		`                                                                                                                                                                                           ,38793]}}`,
+		`                                                                                                                                                                                            38793]}}`,
+		`                                                                                                                                                                                                 ]}}`,
+		`                                                                                                                                                                                                  }}`,
+		`                                                                                                                                                                                                   }`,
+	}
+
+	pj := internalParsedJson{}
+	pj.indexChans = make(chan indexChan, 16)
+
+	// No need to spawn go-routine since the channel is large enough
+	pj.Message = []byte(demo_json)
+	pj.findStructuralIndices()
+
+	ipos, pos := 0, ^uint64(0)
+	for ic := range pj.indexChans {
+		if ic.index == -1 {
+			break
+		}
+		for j := 0; j < ic.length; j++ {
+			pos += uint64((*ic.indexes)[j])
+			result := fmt.Sprintf("%s%s", strings.Repeat(" ", int(pos)), demo_json[pos:])
+			// fmt.Printf("`%s`,\n", result)
+			if result != parsed[ipos] {
+				t.Errorf("TestFindStructuralBits: got: %s want: %s", result, parsed[ipos])
+			}
+			ipos++
+		}
+	}
+}
+
+func BenchmarkStage1(b *testing.B) {
+	msg := loadCompressed(b, "twitter")
+
+	b.SetBytes(int64(len(msg)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	pj := internalParsedJson{}
+	pj.Message = msg
+	for i := 0; i < b.N; i++ {
+		// Create new channel (large enough so we won't block)
+		pj.indexChans = make(chan indexChan, 128)
+		pj.findStructuralIndices()
+	}
+}
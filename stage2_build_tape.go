@@ -1,6 +1,3 @@
-//go:build !noasm && !appengine && gc
-// +build !noasm,!appengine,gc
-
 /*
  * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
  *
@@ -23,6 +20,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"unicode/utf8"
 )
 
 // Constants for "return address" modes
@@ -38,6 +36,12 @@ func updateChar(pj *internalParsedJson, idx_in uint64) (done bool, idx uint64) {
 		if done {
 			return
 		}
+		if w := pj.indexesChan.window; w != nil {
+			// Continue in the next window of the input
+			idx_in -= w.shift
+			pj.Message = w.msg
+			pj.window = w
+		}
 	}
 	idx = idx_in + uint64(pj.indexesChan.indexes[pj.indexesChan.index])
 	pj.indexesChan.index++
@@ -60,6 +64,22 @@ func updateCharDebug(pj *internalParsedJson, idx_in uint64) (done bool, idx uint
 	return
 }
 
+// addRoot records the location of a root value starting at idx.
+// line is the line of idx when parsing newline delimited JSON,
+// otherwise lines are counted from the previous root.
+func (pj *internalParsedJson) addRoot(idx uint64, line int) {
+	if pj.separator != separatorNewline {
+		line = 1
+		if n := len(pj.roots); n > 0 {
+			prev := pj.roots[n-1]
+			line = prev.line + bytes.Count(pj.Message[prev.start:idx], []byte{'\n'})
+		}
+	}
+	pj.roots = append(pj.roots, rootLocation{start: idx, line: line})
+	// The root tag has just been written.
+	pj.rootTape = append(pj.rootTape, pj.get_current_loc()-1)
+}
+
 func peekSize(pj *internalParsedJson) uint64 {
 	if pj.indexesChan.index >= pj.indexesChan.length {
 		//panic("cannot peek the size") // should never happen since last string element should be saved for next buffer
@@ -69,11 +89,73 @@ func peekSize(pj *internalParsedJson) uint64 {
 	return uint64(pj.indexesChan.indexes[pj.indexesChan.index])
 }
 
-func parseString(pj *ParsedJson, idx uint64, maxStringSize uint64, needCopy bool) bool {
-	size := uint64(0)
+func parseString(pj *internalParsedJson, idx uint64, maxStringSize uint64, needCopy bool) bool {
+	offset, size, ok := pj.decodeString(idx, maxStringSize, needCopy)
+	if !ok {
+		return false
+	}
+	pj.write_tape(offset, '"')
+	// put length onto the tape
+	pj.Tape = append(pj.Tape, size)
+	return true
+}
+
+// decodeString validates the string starting at pj.Message[idx]
+// and returns the tape offset and length of its content.
+// Strings that must be copied or unescaped are added to pj.Strings.
+func (pj *internalParsedJson) decodeString(idx uint64, maxStringSize uint64, needCopy bool) (offset, size uint64, ok bool) {
+	buf := pj.stringBuffer(idx, &maxStringSize)
+	if !pj.parseStringValidateOnly(buf, &maxStringSize, &size, &needCopy) {
+		return 0, 0, false
+	}
+	if !needCopy {
+		if pj.utf8Mode == UTF8Replace && !utf8.Valid(buf[1:1+size]) {
+			start := len(pj.Strings.B)
+			pj.Strings.B = appendValidUTF8(pj.Strings.B, buf[1:1+size])
+			return STRINGBUFBIT + uint64(start), uint64(len(pj.Strings.B) - start), true
+		}
+		return idx + 1, size, true
+	}
+	// Make sure we account for at least 32 bytes additional space due to
+	strs := pj.Strings.B
+	requiredLen := uint64(len(strs)) + size + 32
+	if requiredLen >= uint64(cap(strs)) {
+		newSize := uint64(cap(strs) * 2)
+		if newSize < requiredLen {
+			newSize = requiredLen + size // add size once more to account for further space
+		}
+		strs = make([]byte, len(strs), newSize)
+		copy(strs, pj.Strings.B)
+		pj.Strings.B = strs
+	}
+	start := len(strs)
+	_ = pj.parseStringUnescape(buf, &pj.Strings.B) // We can safely ignore the result since we validate above
+	if pj.utf8Mode == UTF8Replace && !utf8.Valid(pj.Strings.B[start:]) {
+		invalid := append([]byte(nil), pj.Strings.B[start:]...)
+		pj.Strings.B = appendValidUTF8(pj.Strings.B[:start], invalid)
+	}
+	return STRINGBUFBIT + uint64(start), uint64(len(pj.Strings.B) - start), true
+}
+
+// validString returns whether the string starting at pj.Message[idx] is valid,
+// without copying it.
+func (pj *internalParsedJson) validString(idx uint64, maxStringSize uint64) bool {
+	buf := pj.stringBuffer(idx, &maxStringSize)
+	var size uint64
+	needCopy := false
+	return pj.parseStringValidateOnly(buf, &maxStringSize, &size, &needCopy)
+}
+
+// stringBuffer returns the message from the string starting at pj.Message[idx],
+// padded so it can be validated.
+func (pj *internalParsedJson) stringBuffer(idx uint64, maxStringSize *uint64) []byte {
 	buf := pj.Message[idx:]
+	if *maxStringSize == 0 {
+		// A string at the root can be the last structural character in the message.
+		*maxStringSize = uint64(len(buf))
+	}
 	// Make sure that we have at least one full YMM word available after maxStringSize into the buffer
-	if len(buf)-int(maxStringSize) < 64 {
+	if len(buf)-int(*maxStringSize) < 64 {
 		if len(buf) > 512-64 { // only allocated if needed
 			paddedBuf := make([]byte, len(buf)+64)
 			copy(paddedBuf, buf)
@@ -84,36 +166,33 @@ func parseString(pj *ParsedJson, idx uint64, maxStringSize uint64, needCopy bool
 			buf = paddedBuf[:]
 		}
 	}
-	if !parseStringSimdValidateOnly(buf, &maxStringSize, &size, &needCopy) {
-		return false
-	}
-	if !needCopy {
-		pj.write_tape(idx+1, '"')
-	} else {
-		// Make sure we account for at least 32 bytes additional space due to
-		strs := pj.Strings.B
-		requiredLen := uint64(len(strs)) + size + 32
-		if requiredLen >= uint64(cap(strs)) {
-			newSize := uint64(cap(strs) * 2)
-			if newSize < requiredLen {
-				newSize = requiredLen + size // add size once more to account for further space
-			}
-			strs = make([]byte, len(strs), newSize)
-			copy(strs, pj.Strings.B)
-			pj.Strings.B = strs
-		}
-		start := len(strs)
-		_ = parseStringSimd(buf, &pj.Strings.B) // We can safely ignore the result since we validate above
-		pj.write_tape(uint64(STRINGBUFBIT+start), '"')
-		size = uint64(len(pj.Strings.B) - start)
-	}
-	// put length onto the tape
-	pj.Tape = append(pj.Tape, size)
-	return true
+	return buf
 }
 
-func addNumber(buf []byte, pj *ParsedJson) bool {
+func addNumber(buf []byte, pj *internalParsedJson) bool {
+	if pj.numbersAsText {
+		n := numberLength(buf)
+		if n == 0 || !isValidNumber(buf[:n]) {
+			return false
+		}
+		pj.addNumberText(buf[:n])
+		return true
+	}
 	tag, val := parseNumber(buf)
+	if pj.decimals && tag == uint64(TagFloat)<<JSONTAGOFFSET {
+		// A float in float notation, which may be exact as a decimal.
+		if mantissa, exp, ok := parseDecimal(buf[:numberLength(buf)]); ok {
+			pj.write_tape(uint64(uint32(exp)), byte(TagDecimal))
+			pj.Tape = append(pj.Tape, uint64(mantissa))
+			return true
+		}
+	}
+	if pj.bigNumbers {
+		if n := bigNumberLength(buf, tag, val); n > 0 {
+			pj.addNumberText(buf[:n])
+			return true
+		}
+	}
 	if tag == 0 {
 		return false
 	}
@@ -121,6 +200,41 @@ func addNumber(buf []byte, pj *ParsedJson) bool {
 	return true
 }
 
+// validNumber returns whether addNumber would accept the number at the start of buf.
+func (pj *internalParsedJson) validNumber(buf []byte) bool {
+	if pj.numbersAsText {
+		n := numberLength(buf)
+		return n > 0 && isValidNumber(buf[:n])
+	}
+	tag, val := parseNumber(buf)
+	return tag != 0 || pj.bigNumbers && bigNumberLength(buf, tag, val) > 0
+}
+
+// addNumberText adds a number to the tape as text.
+// text must point into pj.Message.
+func (pj *internalParsedJson) addNumberText(text []byte) {
+	if pj.copyStrings {
+		start := len(pj.Strings.B)
+		pj.Strings.B = append(pj.Strings.B, text...)
+		pj.write_tape(STRINGBUFBIT+uint64(start), byte(TagNumber))
+	} else {
+		offset := cap(pj.Message) - cap(text)
+		pj.write_tape(uint64(offset), byte(TagNumber))
+	}
+	pj.Tape = append(pj.Tape, uint64(len(text)))
+}
+
+// padAtom returns buf if it is long enough to validate any atom.
+// Otherwise buf is copied to dst and padded with zeros.
+func padAtom(dst *[8]byte, buf []byte) []byte {
+	if len(buf) >= len(dst) {
+		return buf
+	}
+	*dst = [8]byte{}
+	copy(dst[:], buf)
+	return dst[:]
+}
+
 func isValidTrueAtom(buf []byte) bool {
 	if len(buf) >= 5 { // fast path when there is enough space left in the buffer
 		const tv = uint32(0x0000000065757274) // "true    "
@@ -158,12 +272,27 @@ func isValidNullAtom(buf []byte) bool {
 }
 
 func (pj *internalParsedJson) unifiedMachine() (ok, done bool) {
-	buf := pj.Message
 	const addOneForRoot = 1
 
-	idx := ^uint64(0)   // location of the structural character in the input (buf)
+	idx := ^uint64(0)   // location of the structural character in the input (pj.Message)
 	offset := uint64(0) // used to contain last element of containing_scope_offset
 
+	// containingScopeOffset also contains the root, so allow one extra entry.
+	maxScopes := ^uint64(0)
+	if pj.maxDepth > 0 {
+		maxScopes = uint64(pj.maxDepth) + 1
+	}
+
+	// Atoms at the root may be at the very end of the message,
+	// so they are validated from a padded copy.
+	var atom [8]byte
+
+	// line of the current root, counted when parsing newline delimited JSON.
+	line := 1
+
+	// skip is set when the value of the current key is not kept by the projection.
+	skip := false
+
 	////////////////////////////// START STATE /////////////////////////////
 	pj.containingScopeOffset = append(pj.containingScopeOffset, (pj.get_current_loc()<<retAddressShift)|retAddressStartConst)
 
@@ -174,7 +303,8 @@ func (pj *internalParsedJson) unifiedMachine() (ok, done bool) {
 		goto succeed
 	}
 continueRoot:
-	switch buf[idx] {
+	pj.addRoot(idx, line)
+	switch pj.Message[idx] {
 	case '{':
 		pj.containingScopeOffset = append(pj.containingScopeOffset, (pj.get_current_loc()<<retAddressShift)|retAddressStartConst)
 		pj.write_tape(0, '{')
@@ -183,7 +313,39 @@ continueRoot:
 		pj.containingScopeOffset = append(pj.containingScopeOffset, (pj.get_current_loc()<<retAddressShift)|retAddressStartConst)
 		pj.write_tape(0, '[')
 		goto arrayBegin
+	case '"':
+		if !parseString(pj, idx, peekSize(pj), pj.copyStrings) {
+			goto failString
+		}
+		goto startContinue
+	case 't':
+		if !isValidTrueAtom(padAtom(&atom, pj.Message[idx:])) {
+			goto failLiteral
+		}
+		pj.write_tape(0, 't')
+		goto startContinue
+	case 'f':
+		if !isValidFalseAtom(padAtom(&atom, pj.Message[idx:])) {
+			goto failLiteral
+		}
+		pj.write_tape(0, 'f')
+		goto startContinue
+	case 'n':
+		if !isValidNullAtom(padAtom(&atom, pj.Message[idx:])) {
+			goto failLiteral
+		}
+		pj.write_tape(0, 'n')
+		goto startContinue
 	default:
+		if pj.Message[idx] == '-' || (pj.Message[idx] >= '0' && pj.Message[idx] <= '9') {
+			if !addNumber(pj.Message[idx:], pj) && !pj.addNonFinite(pj.Message[idx:]) {
+				goto failNumber
+			}
+			goto startContinue
+		}
+		if pj.addNonFinite(pj.Message[idx:]) {
+			goto startContinue
+		}
 		goto fail
 	}
 
@@ -192,16 +354,24 @@ startContinue:
 	if done, idx = updateChar(pj, idx); done {
 		goto succeed
 	} else {
-		// For an ndjson object, wrap up current object, start new root and check for minimum of 1 newline
-		if buf[idx] != '\n' {
-			goto fail
-		}
+		switch pj.separator {
+		case separatorNewline:
+			// For an ndjson object, wrap up current object, start new root and check for minimum of 1 newline
+			if pj.Message[idx] != '\n' {
+				goto failTrailing
+			}
 
-		// Eat any empty lines
-		for buf[idx] == '\n' {
-			if done, idx = updateChar(pj, idx); done {
-				goto succeed
+			// Eat any empty lines
+			for pj.Message[idx] == '\n' {
+				line++
+				if done, idx = updateChar(pj, idx); done {
+					goto succeed
+				}
 			}
+		case separatorWhitespace, separatorRecord:
+			// The next value may follow immediately.
+		default:
+			goto failTrailing
 		}
 
 		// Otherwise close current root
@@ -223,14 +393,27 @@ startContinue:
 	//////////////////////////////// OBJECT STATES /////////////////////////////
 
 object_begin:
+	if uint64(len(pj.containingScopeOffset)) > maxScopes {
+		goto failDepth
+	}
+	if pj.duplicateKeys != DuplicateKeysAllow {
+		pj.beginObjectKeys()
+	}
+	if pj.projection != nil {
+		pj.beginProjection(false)
+	}
 	if done, idx = updateChar(pj, idx); done {
 		goto succeed
 	}
-	switch buf[idx] {
+	switch pj.Message[idx] {
 	case '"':
-		if !parseString(&pj.ParsedJson, idx, peekSize(pj), pj.copyStrings) {
-			goto fail
+		if !parseString(pj, idx, peekSize(pj), pj.copyStrings) {
+			goto failString
+		}
+		if pj.duplicateKeys != DuplicateKeysAllow && !pj.addObjectKey() {
+			goto failDuplicateKey
 		}
+		skip = pj.projection != nil && !pj.projectKey()
 		goto object_key_state
 	case '}':
 		goto scopeEnd // could also go to object_continue
@@ -242,39 +425,48 @@ object_key_state:
 	if done, idx = updateChar(pj, idx); done {
 		goto succeed
 	}
-	if buf[idx] != ':' {
+	if pj.Message[idx] != ':' {
 		goto fail
 	}
 	if done, idx = updateChar(pj, idx); done {
 		goto succeed
 	}
-	switch buf[idx] {
+	if skip {
+		skip = false
+		if idx, ok, done = pj.skipValue(idx, maxScopes); done {
+			goto succeed
+		} else if !ok {
+			return false, done
+		}
+		goto objectContinue
+	}
+	switch pj.Message[idx] {
 	case '"':
-		if !parseString(&pj.ParsedJson, idx, peekSize(pj), pj.copyStrings) {
-			goto fail
+		if !parseString(pj, idx, peekSize(pj), pj.copyStrings) {
+			goto failString
 		}
 
 	case 't':
-		if !isValidTrueAtom(buf[idx:]) {
-			goto fail
+		if !isValidTrueAtom(pj.Message[idx:]) {
+			goto failLiteral
 		}
 		pj.write_tape(0, 't')
 
 	case 'f':
-		if !isValidFalseAtom(buf[idx:]) {
-			goto fail
+		if !isValidFalseAtom(pj.Message[idx:]) {
+			goto failLiteral
 		}
 		pj.write_tape(0, 'f')
 
 	case 'n':
-		if !isValidNullAtom(buf[idx:]) {
-			goto fail
+		if !isValidNullAtom(pj.Message[idx:]) {
+			goto failLiteral
 		}
 		pj.write_tape(0, 'n')
 
 	case '-':
-		if !addNumber(buf[idx:], &pj.ParsedJson) {
-			goto fail
+		if !addNumber(pj.Message[idx:], pj) && !pj.addNonFinite(pj.Message[idx:]) {
+			goto failNumber
 		}
 
 	case '{':
@@ -290,12 +482,15 @@ object_key_state:
 		goto arrayBegin
 
 	default:
-		if buf[idx] >= '0' && buf[idx] <= '9' {
-			if !addNumber(buf[idx:], &pj.ParsedJson) {
-				goto fail
+		if pj.Message[idx] >= '0' && pj.Message[idx] <= '9' {
+			if !addNumber(pj.Message[idx:], pj) {
+				goto failNumber
 			}
 			break
 		}
+		if pj.addNonFinite(pj.Message[idx:]) {
+			break
+		}
 		goto fail
 	}
 
@@ -303,17 +498,24 @@ objectContinue:
 	if done, idx = updateChar(pj, idx); done {
 		goto succeed
 	}
-	switch buf[idx] {
+	switch pj.Message[idx] {
 	case ',':
 		if done, idx = updateChar(pj, idx); done {
 			goto succeed
 		}
-		if buf[idx] != '"' {
+		if pj.Message[idx] != '"' {
+			if pj.Message[idx] == '}' && pj.lenient&LenientTrailingCommas != 0 {
+				goto scopeEnd
+			}
 			goto fail
 		}
-		if !parseString(&pj.ParsedJson, idx, peekSize(pj), pj.copyStrings) {
-			goto fail
+		if !parseString(pj, idx, peekSize(pj), pj.copyStrings) {
+			goto failString
 		}
+		if pj.duplicateKeys != DuplicateKeysAllow && !pj.addObjectKey() {
+			goto failDuplicateKey
+		}
+		skip = pj.projection != nil && !pj.projectKey()
 		goto object_key_state
 
 	case '}':
@@ -325,12 +527,15 @@ objectContinue:
 
 	////////////////////////////// COMMON STATE /////////////////////////////
 scopeEnd:
+	if pj.duplicateKeys == DuplicateKeysLastWins && pj.Message[idx] == '}' {
+		pj.removeDuplicateKeys()
+	}
 	// write our tape location to the header scope
 	offset = pj.containingScopeOffset[len(pj.containingScopeOffset)-1]
 	// drop last element
 	pj.containingScopeOffset = pj.containingScopeOffset[:len(pj.containingScopeOffset)-1]
 
-	pj.write_tape(offset>>retAddressShift, buf[idx])
+	pj.write_tape(offset>>retAddressShift, pj.Message[idx])
 	pj.annotate_previousloc(offset>>retAddressShift, pj.get_current_loc())
 
 	/* goto saved_state*/
@@ -345,43 +550,49 @@ scopeEnd:
 
 	////////////////////////////// ARRAY STATES /////////////////////////////
 arrayBegin:
+	if uint64(len(pj.containingScopeOffset)) > maxScopes {
+		goto failDepth
+	}
+	if pj.projection != nil {
+		pj.beginProjection(true)
+	}
 	if done, idx = updateChar(pj, idx); done {
 		goto succeed
 	}
-	if buf[idx] == ']' {
+	if pj.Message[idx] == ']' {
 		goto scopeEnd // could also go to array_continue
 	}
 
 mainArraySwitch:
 	// we call update char on all paths in, so we can peek at c on the
 	// on paths that can accept a close square brace (post-, and at start)
-	switch buf[idx] {
+	switch pj.Message[idx] {
 	case '"':
-		if !parseString(&pj.ParsedJson, idx, peekSize(pj), pj.copyStrings) {
-			goto fail
+		if !parseString(pj, idx, peekSize(pj), pj.copyStrings) {
+			goto failString
 		}
 	case 't':
-		if !isValidTrueAtom(buf[idx:]) {
-			goto fail
+		if !isValidTrueAtom(pj.Message[idx:]) {
+			goto failLiteral
 		}
 		pj.write_tape(0, 't')
 
 	case 'f':
-		if !isValidFalseAtom(buf[idx:]) {
-			goto fail
+		if !isValidFalseAtom(pj.Message[idx:]) {
+			goto failLiteral
 		}
 		pj.write_tape(0, 'f')
 
 	case 'n':
-		if !isValidNullAtom(buf[idx:]) {
-			goto fail
+		if !isValidNullAtom(pj.Message[idx:]) {
+			goto failLiteral
 		}
 		pj.write_tape(0, 'n')
 		/* goto array_continue */
 
 	case '-':
-		if !addNumber(buf[idx:], &pj.ParsedJson) {
-			goto fail
+		if !addNumber(pj.Message[idx:], pj) && !pj.addNonFinite(pj.Message[idx:]) {
+			goto failNumber
 		}
 
 	case '{':
@@ -397,12 +608,19 @@ mainArraySwitch:
 		goto arrayBegin
 
 	default:
-		if buf[idx] >= '0' && buf[idx] <= '9' {
-			if !addNumber(buf[idx:], &pj.ParsedJson) {
-				goto fail
+		if pj.Message[idx] >= '0' && pj.Message[idx] <= '9' {
+			if !addNumber(pj.Message[idx:], pj) {
+				goto failNumber
 			}
 			break
 		}
+		if pj.addNonFinite(pj.Message[idx:]) {
+			break
+		}
+		if pj.Message[idx] == ']' && pj.lenient&LenientTrailingCommas != 0 {
+			// Trailing comma, ']' at the start of an array is handled by arrayBegin.
+			goto scopeEnd
+		}
 		goto fail
 	}
 
@@ -410,7 +628,7 @@ arrayContinue:
 	if done, idx = updateChar(pj, idx); done {
 		goto succeed
 	}
-	switch buf[idx] {
+	switch pj.Message[idx] {
 	case ',':
 		if done, idx = updateChar(pj, idx); done {
 			goto succeed
@@ -432,6 +650,7 @@ succeed:
 
 	// Sanity checks
 	if len(pj.containingScopeOffset) != 0 {
+		pj.errStage2 = newParseError(pj.Message, uint64(len(pj.Message)), 2, ErrorKindUnexpectedEnd)
 		return false, done
 	}
 
@@ -441,7 +660,33 @@ succeed:
 	pj.isvalid = true
 	return true, done
 
+failString:
+	pj.errStage2 = newParseError(pj.Message, idx, 2, ErrorKindInvalidString)
+	return false, done
+
+failLiteral:
+	pj.errStage2 = newParseError(pj.Message, idx, 2, ErrorKindInvalidLiteral)
+	return false, done
+
+failNumber:
+	pj.errStage2 = newParseError(pj.Message, idx, 2, ErrorKindInvalidNumber)
+	return false, done
+
+failDepth:
+	pj.errStage2 = newParseError(pj.Message, idx, 2, ErrorKindDepthExceeded)
+	return false, done
+
+failDuplicateKey:
+	pj.errStage2 = newParseError(pj.Message, idx, 2, ErrorKindDuplicateKey)
+	pj.errStage2.Key = string(pj.lastKey())
+	return false, done
+
+failTrailing:
+	pj.errStage2 = newParseError(pj.Message, idx, 2, ErrorKindTrailingData)
+	return false, done
+
 fail:
+	pj.errStage2 = newParseError(pj.Message, idx, 2, ErrorKindUnexpectedCharacter)
 	return false, done
 }
 
@@ -1,6 +1,3 @@
-//go:build !noasm && !appengine && gc
-// +build !noasm,!appengine,gc
-
 /*
  * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
  *
@@ -24,9 +21,6 @@ import (
 )
 
 func TestStage2BuildTape(t *testing.T) {
-	if !SupportedCPU() {
-		t.SkipNow()
-	}
 	var floatHexRepresentation1 uint64 = 0x69066666666666
 	var floatHexRepresentation2 uint64 = 0x79066666666666
 
@@ -193,7 +187,6 @@ func TestStage2BuildTape(t *testing.T) {
 }
 
 func TestIsValidTrueAtom(t *testing.T) {
-
 	testCases := []struct {
 		input    string
 		expected bool
@@ -216,7 +209,6 @@ func TestIsValidTrueAtom(t *testing.T) {
 }
 
 func TestIsValidFalseAtom(t *testing.T) {
-
 	testCases := []struct {
 		input    string
 		expected bool
@@ -239,7 +231,6 @@ func TestIsValidFalseAtom(t *testing.T) {
 }
 
 func TestIsValidNullAtom(t *testing.T) {
-
 	testCases := []struct {
 		input    string
 		expected bool
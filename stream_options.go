@@ -0,0 +1,171 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// StreamOption is an option for parsing streams.
+type StreamOption func(s *streamOptions) error
+
+// streamOptions are the settings used for parsing a stream.
+type streamOptions struct {
+	chunkSize       int
+	workers         int
+	parserOpts      []ParserOption
+	maxRecordLength int
+	decompress      bool
+	follow          time.Duration // poll interval, 0 if not following
+
+	// records is set if blocks are unchanged parts of the input,
+	// so roots can be located and invalid records skipped.
+	records bool
+
+	// locate is called after each block is read, if set.
+	// It returns a function that moves an error in the block to its location in the input,
+	// for blocks that are changed parts of the input.
+	locate func() func(*ParseError) *ParseError
+
+	// input is closed when reading stops, if set.
+	input io.Closer
+}
+
+// newStreamOptions returns the default stream settings with opts applied.
+func newStreamOptions(opts []StreamOption) (streamOptions, error) {
+	s := streamOptions{
+		chunkSize: streamChunkSize,
+		workers:   (runtime.GOMAXPROCS(0) + 1) / 2,
+	}
+	for _, opt := range opts {
+		if err := opt(&s); err != nil {
+			return s, err
+		}
+	}
+	if s.follow > 0 && s.decompress {
+		return s, errors.New("decompression is not supported when following")
+	}
+	return s, nil
+}
+
+// ErrRecordTooLong is returned when a record exceeds the length set by WithStreamMaxRecordLength.
+var ErrRecordTooLong = errors.New("record exceeds maximum length")
+
+// WithStreamChunkSize sets the approximate number of bytes read and parsed as one block.
+// Each result will contain the records of one block.
+// Smaller blocks lower the latency of results, larger blocks lower the overhead.
+// Blocks are extended to the end of the last record, so they may be bigger than n.
+// Default: 10MB.
+func WithStreamChunkSize(n int) StreamOption {
+	return func(s *streamOptions) error {
+		if n <= 0 {
+			return fmt.Errorf("invalid stream chunk size: %d", n)
+		}
+		s.chunkSize = n
+		return nil
+	}
+}
+
+// WithStreamWorkers sets the number of blocks that can be parsed concurrently.
+// Default: half of GOMAXPROCS, rounded up.
+func WithStreamWorkers(n int) StreamOption {
+	return func(s *streamOptions) error {
+		if n <= 0 {
+			return fmt.Errorf("invalid number of stream workers: %d", n)
+		}
+		s.workers = n
+		return nil
+	}
+}
+
+// WithStreamParserOptions sets the parser options applied to each block.
+func WithStreamParserOptions(opts ...ParserOption) StreamOption {
+	return func(s *streamOptions) error {
+		s.parserOpts = opts
+		return nil
+	}
+}
+
+// WithStreamMaxRecordLength limits the length of each record to n bytes,
+// not counting the newline.
+// A longer record stops the stream with an error wrapping ErrRecordTooLong.
+// The input is read in blocks, so up to n plus the chunk size bytes of the record
+// are read at most before it is rejected.
+// A value <= 0 will remove the limit.
+// Default: no limit.
+func WithStreamMaxRecordLength(n int) StreamOption {
+	return func(s *streamOptions) error {
+		s.maxRecordLength = n
+		return nil
+	}
+}
+
+// WithStreamDecompression detects compressed input from its first bytes,
+// and decompresses gzip, zstd, s2, snappy (framed) and bzip2 streams.
+// Other input is parsed as is.
+// Input is decompressed while the previous blocks are parsed.
+// Offsets of records and errors are in the decompressed stream.
+func WithStreamDecompression() StreamOption {
+	return func(s *streamOptions) error {
+		s.decompress = true
+		return nil
+	}
+}
+
+// WithStreamFollow keeps reading at the end of the input, like tail -f,
+// and checks for more data every poll interval.
+// Only complete lines are parsed, so each record is returned once its newline has been written.
+// When the input is a file, it is opened again by name if it has been replaced,
+// for example by log rotation, and read from the start if it has been truncated.
+// An incomplete last line is dropped in both cases.
+// The stream only ends when the context is cancelled or an error occurs.
+// Record offsets and lines continue across replaced files,
+// but don't count the incomplete lines that were dropped.
+// Following cannot be combined with WithStreamDecompression.
+func WithStreamFollow(poll time.Duration) StreamOption {
+	return func(s *streamOptions) error {
+		if poll <= 0 {
+			return fmt.Errorf("invalid follow poll interval: %v", poll)
+		}
+		s.follow = poll
+		return nil
+	}
+}
+
+// newValueStreamOptions returns the settings for a stream of concatenated values or a JSON text sequence.
+// Options that only apply to NDJSON are rejected.
+func newValueStreamOptions(opts []StreamOption) (streamOptions, error) {
+	s, err := newStreamOptions(opts)
+	if err != nil {
+		return s, err
+	}
+	if s.maxRecordLength > 0 || s.decompress || s.follow > 0 {
+		return s, errors.New("record length limit, decompression and following are only supported for NDJSON streams")
+	}
+	s.records = true
+	return s, nil
+}
+
+// streamParserOptions returns the default stream settings with the parser options opts.
+func streamParserOptions(opts []ParserOption) streamOptions {
+	s, _ := newStreamOptions([]StreamOption{WithStreamParserOptions(opts...)})
+	return s
+}
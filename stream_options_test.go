@@ -0,0 +1,127 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// streamNDRecords parses r with opts and returns the values of the records
+// and the number of results, or the first error.
+func streamNDRecords(r io.Reader, opts ...StreamOption) (values []interface{}, results int, err error) {
+	return streamValues(func(res chan<- Stream) {
+		ParseNDStreamWithOptions(context.Background(), r, res, nil, opts...)
+	})
+}
+
+func TestParseNDStreamWithOptions(t *testing.T) {
+	var sb strings.Builder
+	var want []interface{}
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "{\"i\":%d}\n", i)
+		want = append(want, map[string]interface{}{"i": int64(i)})
+	}
+	input := sb.String()
+
+	for _, workers := range []int{1, 4} {
+		got, results, err := streamNDRecords(strings.NewReader(input), WithStreamChunkSize(100), WithStreamWorkers(workers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("workers %d: got %d records, want %d", workers, len(got), len(want))
+		}
+		for i := range got {
+			if !reflect.DeepEqual(got[i], want[i]) {
+				t.Fatalf("workers %d: record %d: got %v, want %v", workers, i, got[i], want[i])
+			}
+		}
+		if results < len(input)/200 {
+			t.Errorf("workers %d: got %d results for %d bytes in blocks of 100", workers, results, len(input))
+		}
+	}
+
+	t.Run("parser options", func(t *testing.T) {
+		_, _, err := streamNDRecords(strings.NewReader("{\"a\":1}\n{\"a\":{\"b\":2}}\n"), WithStreamParserOptions(WithMaxDepth(1)))
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Kind != ErrorKindDepthExceeded {
+			t.Errorf("got %v, want %v", err, ErrorKindDepthExceeded)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, opt := range []StreamOption{WithStreamChunkSize(0), WithStreamWorkers(-1), WithStreamParserOptions(WithUTF8Mode(100))} {
+			if _, _, err := streamNDRecords(strings.NewReader(input), opt); err == nil {
+				t.Error("expected error")
+			}
+		}
+	})
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestStreamMaxRecordLength(t *testing.T) {
+	const max = 100
+	short := strings.Repeat(`{"a":"`+strings.Repeat("x", max-10)+"\"}\n", 50)
+	if _, _, err := streamNDRecords(strings.NewReader(short), WithStreamChunkSize(64), WithStreamMaxRecordLength(max)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A long record within a block.
+	input := short + `{"a":"` + strings.Repeat("x", max) + "\"}\n" + short
+	_, _, err := streamNDRecords(strings.NewReader(input), WithStreamMaxRecordLength(max))
+	if !errors.Is(err, ErrRecordTooLong) || !strings.Contains(err.Error(), fmt.Sprintf("offset %d", len(short))) {
+		t.Errorf("got %v, want %v at offset %d", err, ErrRecordTooLong, len(short))
+	}
+
+	// An endless record is read up to the limit plus a block,
+	// and a block buffered ahead of it.
+	r := &countingReader{r: &endlessRecord{}}
+	_, _, err = streamNDRecords(r, WithStreamChunkSize(1<<10), WithStreamMaxRecordLength(4<<10))
+	if !errors.Is(err, ErrRecordTooLong) {
+		t.Errorf("got %v, want %v", err, ErrRecordTooLong)
+	}
+	if r.n > 4<<10+2<<10 {
+		t.Errorf("read %d bytes of endless record", r.n)
+	}
+}
+
+// endlessRecord is a reader that returns a record that never ends.
+type endlessRecord struct{}
+
+func (endlessRecord) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	return len(p), nil
+}
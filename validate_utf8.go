@@ -0,0 +1,93 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"encoding/binary"
+	"unicode/utf8"
+)
+
+// validateUTF8 checks that buf is valid UTF-8.
+// Unless final is set, buf may end with an incomplete sequence.
+// It is not included in the returned length n, so it can be validated
+// together with the input that follows.
+// bad is the offset of the first invalid sequence, or -1 if none was found.
+func validateUTF8(buf []byte, final, simd bool) (n, bad int) {
+	start := 0
+	blocks, ok := validateUTF8Blocks(buf, simd)
+	if !ok {
+		// Locate the error.
+		return validateUTF8Generic(buf, final)
+	}
+	if blocks > 0 {
+		// A sequence starting in the last 3 bytes of the blocks may continue after them.
+		start = blocks - 3
+		for start < blocks && buf[start]&0xc0 == 0x80 {
+			start++
+		}
+	}
+	n, bad = validateUTF8Generic(buf[start:], final)
+	if bad >= 0 {
+		bad += start
+	}
+	return start + n, bad
+}
+
+// validateUTF8Generic is the pure Go version of validateUTF8.
+func validateUTF8Generic(buf []byte, final bool) (n, bad int) {
+	i := 0
+	for i < len(buf) {
+		// Skip ASCII 8 bytes at a time.
+		if i+8 <= len(buf) && binary.LittleEndian.Uint64(buf[i:])&0x8080808080808080 == 0 {
+			i += 8
+			continue
+		}
+		if buf[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(buf[i:])
+		if r == utf8.RuneError && size == 1 {
+			if !final && !utf8.FullRune(buf[i:]) {
+				return i, -1
+			}
+			return i, i
+		}
+		i += size
+	}
+	return i, -1
+}
+
+// appendValidUTF8 appends src to dst, replacing each byte
+// that is not part of a valid UTF-8 sequence with U+FFFD.
+func appendValidUTF8(dst, src []byte) []byte {
+	for i := 0; i < len(src); {
+		if src[i] < utf8.RuneSelf {
+			dst = append(dst, src[i])
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(src[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, "\uFFFD"...)
+		} else {
+			dst = append(dst, src[i:i+size]...)
+		}
+		i += size
+	}
+	return dst
+}
@@ -0,0 +1,39 @@
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
+
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"unsafe"
+)
+
+//go:noescape
+func _validate_utf8_avx2(buf unsafe.Pointer, n uint64) (ok bool)
+
+// validateUTF8Blocks validates the longest prefix of buf that is a multiple of 32 bytes
+// and returns its length.
+// A sequence that is incomplete at the end of the prefix is not reported.
+// If simd is false nothing is validated.
+func validateUTF8Blocks(buf []byte, simd bool) (n int, ok bool) {
+	n = len(buf) &^ 31
+	if !simd || n == 0 {
+		return 0, true
+	}
+	return n, _validate_utf8_avx2(unsafe.Pointer(&buf[0]), uint64(n))
+}
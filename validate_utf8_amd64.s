@@ -0,0 +1,123 @@
+//go:build !noasm && !appengine && gc
+// +build !noasm,!appengine,gc
+
+#include "textflag.h"
+
+// Lookup tables for the UTF-8 validation by Keiser and Lemire,
+// "Validating UTF-8 In Less Than One Instruction Per Byte".
+// Each table is indexed by a nibble and repeated for both lanes.
+//
+// Error bits:
+//   0x01 too short, 0x02 too long, 0x04 overlong 3 byte, 0x08 too large,
+//   0x10 surrogate, 0x20 overlong 2 byte, 0x40 too large 1000 / overlong 4 byte,
+//   0x80 two continuations.
+
+// Indexed by the high nibble of the previous byte.
+DATA utf8Byte1High<>+0x00(SB)/8, $0x0202020202020202
+DATA utf8Byte1High<>+0x08(SB)/8, $0x4915012180808080
+DATA utf8Byte1High<>+0x10(SB)/8, $0x0202020202020202
+DATA utf8Byte1High<>+0x18(SB)/8, $0x4915012180808080
+GLOBL utf8Byte1High<>(SB), (RODATA|NOPTR), $32
+
+// Indexed by the low nibble of the previous byte.
+DATA utf8Byte1Low<>+0x00(SB)/8, $0xcbcbcb8b8383a3e7
+DATA utf8Byte1Low<>+0x08(SB)/8, $0xcbcbdbcbcbcbcbcb
+DATA utf8Byte1Low<>+0x10(SB)/8, $0xcbcbcb8b8383a3e7
+DATA utf8Byte1Low<>+0x18(SB)/8, $0xcbcbdbcbcbcbcbcb
+GLOBL utf8Byte1Low<>(SB), (RODATA|NOPTR), $32
+
+// Indexed by the high nibble of the current byte.
+DATA utf8Byte2High<>+0x00(SB)/8, $0x0101010101010101
+DATA utf8Byte2High<>+0x08(SB)/8, $0x01010101babaaee6
+DATA utf8Byte2High<>+0x10(SB)/8, $0x0101010101010101
+DATA utf8Byte2High<>+0x18(SB)/8, $0x01010101babaaee6
+GLOBL utf8Byte2High<>(SB), (RODATA|NOPTR), $32
+
+// Maximum values of the last three bytes of a block that do not start
+// a sequence continuing into the next block.
+DATA utf8Incomplete<>+0x00(SB)/8, $0xffffffffffffffff
+DATA utf8Incomplete<>+0x08(SB)/8, $0xffffffffffffffff
+DATA utf8Incomplete<>+0x10(SB)/8, $0xffffffffffffffff
+DATA utf8Incomplete<>+0x18(SB)/8, $0xbfdfefffffffffff
+GLOBL utf8Incomplete<>(SB), (RODATA|NOPTR), $32
+
+DATA utf8Consts<>+0x00(SB)/1, $0x0f
+DATA utf8Consts<>+0x01(SB)/1, $0x60
+DATA utf8Consts<>+0x02(SB)/1, $0x70
+DATA utf8Consts<>+0x03(SB)/1, $0x80
+GLOBL utf8Consts<>(SB), (RODATA|NOPTR), $4
+
+// func _validate_utf8_avx2(buf unsafe.Pointer, n uint64) (ok bool)
+// n must be a multiple of 32.
+// A sequence that is incomplete at the end of buf is not reported.
+TEXT ·_validate_utf8_avx2(SB), NOSPLIT, $0-17
+	MOVQ buf+0(FP), SI
+	MOVQ n+8(FP), CX
+
+	LEAQ         utf8Consts<>(SB), AX
+	VPBROADCASTB 0(AX), Y15
+	VPBROADCASTB 1(AX), Y10
+	VPBROADCASTB 2(AX), Y9
+	VPBROADCASTB 3(AX), Y8
+	VMOVDQU      utf8Byte1High<>(SB), Y14
+	VMOVDQU      utf8Byte1Low<>(SB), Y13
+	VMOVDQU      utf8Byte2High<>(SB), Y12
+	VMOVDQU      utf8Incomplete<>(SB), Y11
+
+	VPXOR Y0, Y0, Y0 // previous block
+	VPXOR Y1, Y1, Y1 // errors
+	VPXOR Y2, Y2, Y2 // previous block is incomplete
+
+loop:
+	CMPQ CX, $32
+	JB   done
+	VMOVDQU   (SI), Y3
+	VPMOVMSKB Y3, AX
+	TESTL     AX, AX
+	JNZ       multibyte
+
+	// ASCII only, so the previous block must have been complete.
+	VPOR  Y2, Y1, Y1
+	VPXOR Y2, Y2, Y2
+	VMOVDQU Y3, Y0
+	JMP   next
+
+multibyte:
+	// Y4 = high lane of the previous block, low lane of the current block.
+	VPERM2I128 $0x21, Y3, Y0, Y4
+	VPALIGNR   $15, Y4, Y3, Y5 // prev1
+
+	VPSRLW  $4, Y5, Y6
+	VPAND   Y15, Y6, Y6
+	VPSHUFB Y6, Y14, Y6
+	VPAND   Y15, Y5, Y7
+	VPSHUFB Y7, Y13, Y7
+	VPAND   Y7, Y6, Y6
+	VPSRLW  $4, Y3, Y7
+	VPAND   Y15, Y7, Y7
+	VPSHUFB Y7, Y12, Y7
+	VPAND   Y7, Y6, Y6 // special cases
+
+	// Third and fourth bytes of a sequence must be continuations.
+	VPALIGNR $14, Y4, Y3, Y5 // prev2
+	VPALIGNR $13, Y4, Y3, Y7 // prev3
+	VPSUBUSB Y10, Y5, Y5
+	VPSUBUSB Y9, Y7, Y7
+	VPOR     Y7, Y5, Y5
+	VPAND    Y8, Y5, Y5
+	VPXOR    Y6, Y5, Y5
+	VPOR     Y5, Y1, Y1
+
+	VPSUBUSB Y11, Y3, Y2
+	VMOVDQU  Y3, Y0
+
+next:
+	ADDQ $32, SI
+	SUBQ $32, CX
+	JMP  loop
+
+done:
+	VPTEST    Y1, Y1
+	SETEQ     ok+16(FP)
+	VZEROUPPER
+	RET
@@ -0,0 +1,108 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simdjson
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestValidateUTF8(t *testing.T) {
+	pieces := []string{
+		"a", "abcdefgh", "é", "€", "\U0001d11e", "\U0010ffff",
+		// Invalid
+		"\xff", "\x80", "\xc0\x80", "\xc1\xbf", "\xe0\x80\x80", "\xed\xa0\x80", "\xf0\x80\x80\x80", "\xf4\x90\x80\x80", "\xf8",
+		// Truncated
+		"\xc3", "\xe2\x82", "\xf0\x9d\x84",
+	}
+	rng := rand.New(rand.NewSource(0))
+	for _, simd := range []bool{false, SupportedCPU()} {
+		for i := 0; i < 100000; i++ {
+			var buf []byte
+			n := rng.Intn(100)
+			for j := 0; j < n; j++ {
+				// Mostly valid pieces.
+				p := pieces[rng.Intn(6)]
+				if rng.Intn(20) == 0 {
+					p = pieces[rng.Intn(len(pieces))]
+				}
+				buf = append(buf, p...)
+			}
+			want := -1
+			for i := 0; i < len(buf); {
+				r, size := utf8.DecodeRune(buf[i:])
+				if r == utf8.RuneError && size == 1 {
+					want = i
+					break
+				}
+				i += size
+			}
+
+			if _, bad := validateUTF8(buf, true, simd); bad != want {
+				t.Fatalf("simd: %v, input %x: got %d, want %d", simd, buf, bad, want)
+			}
+
+			// Validating in two parts must give the same result.
+			split := rng.Intn(len(buf) + 1)
+			n1, bad := validateUTF8(buf[:split], false, simd)
+			if bad < 0 {
+				if n1 < split-3 || n1 > split {
+					t.Fatalf("simd: %v, input %x split at %d: validated %d", simd, buf, split, n1)
+				}
+				var n2 int
+				n2, bad = validateUTF8(buf[n1:], true, simd)
+				if bad >= 0 {
+					bad += n1
+				} else if n1+n2 != len(buf) {
+					t.Fatalf("simd: %v, input %x split at %d: validated %d", simd, buf, split, n1+n2)
+				}
+			}
+			if bad != want {
+				t.Fatalf("simd: %v, input %x split at %d: got %d, want %d", simd, buf, split, bad, want)
+			}
+		}
+	}
+}
+
+func TestAppendValidUTF8(t *testing.T) {
+	src := []byte("a\xffb\xe2\x82\xac\xe2\x82c\xed\xa0\x80")
+	want := []byte("a\uFFFDb€\uFFFD\uFFFDc\uFFFD\uFFFD\uFFFD")
+	if got := appendValidUTF8(nil, src); !bytes.Equal(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func benchmarkValidateUTF8(b *testing.B, simd bool) {
+	buf := bytes.Repeat([]byte("The quick brown æøå jumps over the lazy €\U0001d11e. "), 1000)
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, bad := validateUTF8(buf, true, simd); bad >= 0 {
+			b.Fatal("invalid")
+		}
+	}
+}
+
+func BenchmarkValidateUTF8(b *testing.B) {
+	b.Run("generic", func(b *testing.B) { benchmarkValidateUTF8(b, false) })
+	if SupportedCPU() {
+		b.Run("simd", func(b *testing.B) { benchmarkValidateUTF8(b, true) })
+	}
+}